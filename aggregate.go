@@ -0,0 +1,271 @@
+package slog
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// AggregateCountMetadataKey is the metadata key for the number of events an AggregatingLogger combined.
+	AggregateCountMetadataKey = "count"
+	// AggregateSamplesMetadataKey is the metadata key for a sample of the distinct metadata of the events an
+	// AggregatingLogger combined.
+	AggregateSamplesMetadataKey = "samples"
+	// AggregateValuesMetadataKey is the metadata key for the distinct values of each metadata key which varied across
+	// the events an AggregatingLogger combined, if enabled with WithDistinctValues.
+	AggregateValuesMetadataKey = "values"
+)
+
+const (
+	// maxAggregateGroups bounds the number of groups an AggregatingLogger tracks at once. Events which would start a
+	// new group beyond this are forwarded immediately.
+	maxAggregateGroups = 1000
+	// maxAggregateSamples bounds the number of distinct metadata samples kept for each group.
+	maxAggregateSamples = 5
+	// maxAggregateDistinctValues bounds the number of distinct values kept for each metadata key of each group.
+	maxAggregateDistinctValues = 100
+	// maxAggregateValueKeys bounds the number of metadata keys whose distinct values are kept for each group.
+	maxAggregateValueKeys = 50
+)
+
+type aggregateKey struct {
+	severity Severity
+	message  string
+}
+
+// aggregate is a group of similar events being combined.
+type aggregate struct {
+	first   Event
+	count   int
+	samples []map[string]interface{}
+	values  map[string]*distinctValues
+	timer   *time.Timer
+	// deadline is when the group is emitted even if similar events keep arriving.
+	deadline time.Time
+}
+
+// distinctValues is the distinct values of a metadata key across a group, in the order they were first seen.
+type distinctValues struct {
+	seen   map[interface{}]bool
+	values []interface{}
+}
+
+// stringForm is the key under which a value which can't be used as a map key is recorded as seen.
+type stringForm string
+
+// An AggregatingLogger reduces the noise of bursts of similar events, such as errors from a flapping dependency. Events
+// with the same severity and original message are grouped, and a single representative event is forwarded in place of
+// the group once no similar event has been logged for the window. So that a continuous stream of events isn't held
+// back indefinitely, a group is also forwarded once it reaches its maximum age (see WithMaxAggregateAge).
+type AggregatingLogger struct {
+	next           Logger
+	window         time.Duration
+	maxAge         time.Duration
+	distinctValues int
+
+	m      sync.Mutex
+	groups map[aggregateKey]*aggregate
+}
+
+// An AggregateOption configures an AggregatingLogger.
+type AggregateOption func(*AggregatingLogger)
+
+// WithDistinctValues makes an AggregatingLogger collect up to n distinct values of each metadata key across a group,
+// such as the IDs of all the users affected by an error, rather than only the representative event's value. Keys
+// whose value varied are included in the representative event's metadata under AggregateValuesMetadataKey, as a map
+// from each key to a slice of its values. n is capped at 100, to bound memory use.
+//
+// Values are compared with ==, except for those whose types can't be used as map keys (or may contain values which
+// can't, such as structs); these are compared, and collected, by their string form.
+func WithDistinctValues(n int) AggregateOption {
+	return func(l *AggregatingLogger) {
+		if n > maxAggregateDistinctValues {
+			n = maxAggregateDistinctValues
+		}
+		l.distinctValues = n
+	}
+}
+
+// WithMaxAggregateAge bounds how long an AggregatingLogger holds a group, measured from its first event, even if similar
+// events keep being logged. By default, it is ten times the window; it is never less than the window.
+func WithMaxAggregateAge(d time.Duration) AggregateOption {
+	return func(l *AggregatingLogger) {
+		l.maxAge = d
+	}
+}
+
+// NewAggregatingLogger creates an AggregatingLogger which forwards events to next, grouping those logged within window
+// of the previous event of their group.
+func NewAggregatingLogger(next Logger, window time.Duration, opts ...AggregateOption) *AggregatingLogger {
+	l := &AggregatingLogger{
+		next:   next,
+		window: window,
+		maxAge: 10 * window,
+		groups: map[aggregateKey]*aggregate{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.maxAge < window {
+		l.maxAge = window
+	}
+	return l
+}
+
+// Log adds the events to their groups, extending each group's window. If too many groups are already pending, events
+// which would start a new group are forwarded immediately instead.
+func (l *AggregatingLogger) Log(evs ...Event) {
+	var immediate []Event
+	now := time.Now()
+	l.m.Lock()
+	for _, e := range evs {
+		key := aggregateKey{e.Severity, e.OriginalMessage}
+		if key.message == "" {
+			key.message = e.Message
+		}
+
+		g, ok := l.groups[key]
+		if !ok {
+			if len(l.groups) >= maxAggregateGroups {
+				immediate = append(immediate, e)
+				continue
+			}
+			g = &aggregate{first: e, deadline: now.Add(l.maxAge)}
+			g.timer = time.AfterFunc(l.window, func() {
+				l.emit(key, g)
+			})
+			l.groups[key] = g
+		} else {
+			// If the timer has already fired, emit is waiting for the lock, and will forward this event with the group
+			wait := l.window
+			if untilDeadline := g.deadline.Sub(now); untilDeadline < wait {
+				wait = untilDeadline
+			}
+			g.timer.Reset(wait)
+		}
+		g.count++
+		g.addSample(e.Metadata)
+		if l.distinctValues > 0 {
+			g.addValues(e.Metadata, l.distinctValues)
+		}
+	}
+	l.m.Unlock()
+
+	if len(immediate) > 0 {
+		l.next.Log(immediate...)
+	}
+}
+
+// Flush forwards every pending group, regardless of its window, and then flushes the underlying Logger.
+func (l *AggregatingLogger) Flush() error {
+	l.m.Lock()
+	groups := l.groups
+	l.groups = map[aggregateKey]*aggregate{}
+	l.m.Unlock()
+
+	if len(groups) > 0 {
+		evs := make([]Event, 0, len(groups))
+		for _, g := range groups {
+			g.timer.Stop()
+			evs = append(evs, g.event())
+		}
+		l.next.Log(evs...)
+	}
+	return l.next.Flush()
+}
+
+// emit forwards the group when its window closes, or it reaches its maximum age, unless it has already been forwarded.
+func (l *AggregatingLogger) emit(key aggregateKey, g *aggregate) {
+	l.m.Lock()
+	if l.groups[key] != g {
+		l.m.Unlock()
+		return
+	}
+	delete(l.groups, key)
+	l.m.Unlock()
+	l.next.Log(g.event())
+}
+
+// addSample records the metadata if it is distinct from the samples so far, and there is room.
+func (g *aggregate) addSample(metadata map[string]interface{}) {
+	if len(metadata) == 0 || len(g.samples) >= maxAggregateSamples {
+		return
+	}
+	for _, sample := range g.samples {
+		if reflect.DeepEqual(sample, metadata) {
+			return
+		}
+	}
+	g.samples = append(g.samples, metadata)
+}
+
+// addValues records the distinct values of each metadata key, up to n for each.
+func (g *aggregate) addValues(metadata map[string]interface{}, n int) {
+	for k, v := range metadata {
+		dv, ok := g.values[k]
+		if !ok {
+			if len(g.values) >= maxAggregateValueKeys {
+				continue
+			}
+			if g.values == nil {
+				g.values = map[string]*distinctValues{}
+			}
+			dv = &distinctValues{seen: map[interface{}]bool{}}
+			g.values[k] = dv
+		}
+		if len(dv.values) >= n {
+			continue
+		}
+
+		key := interface{}(nil)
+		if v == nil || isHashable(reflect.TypeOf(v)) {
+			key = v
+		} else {
+			v = fmt.Sprint(v)
+			key = stringForm(v.(string))
+		}
+		if !dv.seen[key] {
+			dv.seen[key] = true
+			dv.values = append(dv.values, v)
+		}
+	}
+}
+
+// isHashable reports whether values of the type can always be used as map keys. Structs, arrays and interfaces are
+// comparable, but may contain values which aren't, so are conservatively excluded.
+func isHashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array, reflect.Interface:
+		return false
+	}
+	return t.Comparable()
+}
+
+// event returns the representative event for the group: its first event, with the number of events in the group and
+// a sample of their distinct metadata added to its metadata. A group of a single event is represented by that event,
+// unchanged.
+func (g *aggregate) event() Event {
+	if g.count == 1 {
+		return g.first
+	}
+	e := g.first
+	metadata := map[string]interface{}{
+		AggregateCountMetadataKey: g.count,
+	}
+	if len(g.samples) > 0 {
+		metadata[AggregateSamplesMetadataKey] = g.samples
+	}
+	values := map[string][]interface{}{}
+	for k, dv := range g.values {
+		if len(dv.values) > 1 {
+			values[k] = dv.values
+		}
+	}
+	if len(values) > 0 {
+		metadata[AggregateValuesMetadataKey] = values
+	}
+	e.Metadata = mergeMetadataOverwrite(mergeMetadata(nil, e.Metadata), metadata)
+	return e
+}
@@ -0,0 +1,159 @@
+package slog
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatingLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		logger.Log(Eventf(ErrorSeverity, ctx, "Failed to call %s", "widgets", map[string]interface{}{
+			"attempt": i % 3,
+		}))
+	}
+	logger.Log(Eventf(WarnSeverity, ctx, "Failed to call %s", "widgets"))
+	assert.Empty(t, next.Events())
+
+	require.NoError(t, logger.Flush())
+	events := next.Events()
+	require.Len(t, events, 2)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Severity > events[j].Severity
+	})
+
+	assert.Equal(t, ErrorSeverity, events[0].Severity)
+	assert.Equal(t, "Failed to call widgets", events[0].Message)
+	assert.Equal(t, 10, events[0].Metadata[AggregateCountMetadataKey])
+	assert.Equal(t, 0, events[0].Metadata["attempt"])
+	assert.Equal(t, []map[string]interface{}{
+		{"attempt": 0},
+		{"attempt": 1},
+		{"attempt": 2},
+	}, events[0].Metadata[AggregateSamplesMetadataKey])
+
+	// A group of one is forwarded unchanged
+	assert.Equal(t, WarnSeverity, events[1].Severity)
+	assert.Empty(t, events[1].Metadata)
+}
+
+func TestAggregatingLoggerWindow(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, 10*time.Millisecond)
+
+	logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed"), Eventf(ErrorSeverity, context.Background(), "Failed"))
+	waitFor(t, func() bool {
+		return len(next.Events()) == 1
+	})
+	assert.Equal(t, 2, next.Events()[0].Metadata[AggregateCountMetadataKey])
+
+	// The group was emitted, so isn't emitted again
+	require.NoError(t, logger.Flush())
+	assert.Len(t, next.Events(), 1)
+}
+
+func TestAggregatingLoggerWindowExtended(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, 100*time.Millisecond)
+
+	// Each event extends the window, so the group outlives the window measured from its first event
+	for i := 0; i < 6; i++ {
+		logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed"))
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Empty(t, next.Events())
+	waitFor(t, func() bool {
+		return next.Len() == 1
+	})
+	assert.Equal(t, 6, next.Events()[0].Metadata[AggregateCountMetadataKey])
+}
+
+func TestAggregatingLoggerMaxAge(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, 100*time.Millisecond, WithMaxAggregateAge(150*time.Millisecond))
+
+	// A continuous stream is still forwarded once its group reaches the maximum age
+	for i := 0; i < 15; i++ {
+		logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed"))
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.NotEmpty(t, next.Events())
+
+	require.NoError(t, logger.Flush())
+	total := 0
+	for _, e := range next.Events() {
+		// A group of one has no count
+		if n, ok := e.Metadata[AggregateCountMetadataKey].(int); ok {
+			total += n
+		} else {
+			total++
+		}
+	}
+	assert.Equal(t, 15, total)
+}
+
+func TestAggregatingLoggerSamplesBounded(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, time.Hour)
+
+	for i := 0; i < 2*maxAggregateSamples; i++ {
+		logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed", map[string]interface{}{"i": i}))
+	}
+	require.NoError(t, logger.Flush())
+	require.Len(t, next.Events(), 1)
+	assert.Len(t, next.Events()[0].Metadata[AggregateSamplesMetadataKey], maxAggregateSamples)
+}
+
+func TestAggregatingLoggerGroupsBounded(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, time.Hour)
+
+	for i := 0; i < maxAggregateGroups+5; i++ {
+		logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed "+strconv.Itoa(i)))
+	}
+	assert.Len(t, next.Events(), 5)
+	require.NoError(t, logger.Flush())
+	assert.Len(t, next.Events(), maxAggregateGroups+5)
+}
+
+func TestAggregatingLoggerDistinctValues(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, time.Hour, WithDistinctValues(3))
+
+	for _, userID := range []string{"u1", "u2", "u1", "u3", "u4"} {
+		logger.Log(Eventf(ErrorSeverity, context.Background(), "Failed to load user", map[string]interface{}{
+			"user_id": userID,
+			"service": "users",
+			"tags":    []string{"a", userID},
+		}))
+	}
+	require.NoError(t, logger.Flush())
+	require.Len(t, next.Events(), 1)
+
+	// Keys which didn't vary aren't included, and values which can't be map keys are collected by their string form
+	assert.Equal(t, map[string][]interface{}{
+		"user_id": {"u1", "u2", "u3"},
+		"tags":    {"[a u1]", "[a u2]", "[a u3]"},
+	}, next.Events()[0].Metadata[AggregateValuesMetadataKey])
+}
+
+func TestAggregatingLoggerDistinctValuesDisabled(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewAggregatingLogger(next, time.Hour)
+
+	logger.Log(
+		Eventf(ErrorSeverity, context.Background(), "Failed", map[string]interface{}{"user_id": "u1"}),
+		Eventf(ErrorSeverity, context.Background(), "Failed", map[string]interface{}{"user_id": "u2"}))
+	require.NoError(t, logger.Flush())
+	require.Len(t, next.Events(), 1)
+	assert.NotContains(t, next.Events()[0].Metadata, AggregateValuesMetadataKey)
+}
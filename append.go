@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	appendableKeys  map[string]struct{}
+	appendableKeysM sync.RWMutex
+)
+
+// SetAppendableMetadataKeys configures metadata keys whose values are accumulated, rather than overwritten, when they
+// are provided by more than one metadata source (context params, logMetadataProvider params and inline maps).
+//
+// When an appendable key is provided by multiple sources, the event's metadata contains a []interface{} of the values
+// in increasing order of source precedence. Slice values are flattened into the result, so a source providing
+// []string{"a", "b"} contributes "a" and "b"; byte slices are treated as scalars. A key provided by only one source is
+// left as-is.
+func SetAppendableMetadataKeys(keys ...string) {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+
+	appendableKeysM.Lock()
+	defer appendableKeysM.Unlock()
+	appendableKeys = set
+}
+
+func appendableMetadataKeys() map[string]struct{} {
+	appendableKeysM.RLock()
+	defer appendableKeysM.RUnlock()
+	return appendableKeys
+}
+
+// appendMetadataValues combines two values for an appendable key into a single []interface{}.
+func appendMetadataValues(existing, new interface{}) []interface{} {
+	return appendFlattened(appendFlattened(nil, existing), new)
+}
+
+func appendFlattened(result []interface{}, v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return append(result, v)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		result = append(result, rv.Index(i).Interface())
+	}
+	return result
+}
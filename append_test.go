@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendableMetadataKeys(t *testing.T) {
+	SetAppendableMetadataKeys("tags")
+	defer SetAppendableMetadataKeys()
+
+	ctx := WithParams(context.Background(), map[string]string{
+		"tags":  "context",
+		"other": "context",
+	})
+
+	testCases := []struct {
+		desc     string
+		ctx      context.Context
+		params   []interface{}
+		expected map[string]interface{}
+	}{
+		{
+			desc: "single source",
+			ctx:  context.Background(),
+			params: []interface{}{
+				map[string]interface{}{"tags": "inline"},
+			},
+			expected: map[string]interface{}{"tags": "inline"},
+		},
+		{
+			desc: "context and provider",
+			ctx:  ctx,
+			params: []interface{}{
+				testLogMetadataProvider{"tags": "provider"},
+			},
+			expected: map[string]interface{}{
+				"tags":  []interface{}{"context", "provider"},
+				"other": "context",
+			},
+		},
+		{
+			desc: "all three sources",
+			ctx:  ctx,
+			params: []interface{}{
+				testLogMetadataProvider{"tags": "provider", "other": "provider"},
+				map[string]interface{}{"tags": "inline"},
+			},
+			expected: map[string]interface{}{
+				"tags":  []interface{}{"context", "provider", "inline"},
+				"other": "provider",
+			},
+		},
+		{
+			desc: "slices are flattened",
+			ctx:  ctx,
+			params: []interface{}{
+				map[string]interface{}{"tags": []string{"a", "b"}},
+			},
+			expected: map[string]interface{}{
+				"tags":  []interface{}{"context", "a", "b"},
+				"other": "context",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			e := Eventf(InfoSeverity, tC.ctx, "foo", tC.params...)
+			assert.Equal(t, tC.expected, e.Metadata)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flushChunkSize is the number of events FlushContext forwards at a time when the BatchLogger has no maximum batch
+// size.
+const flushChunkSize = 100
+
+// A BatchLogger accumulates events and forwards them to another Logger in batches. A batch is emitted when either
+// maxBatch events are pending, or maxDelay has elapsed since the first event in the batch was queued.
+type BatchLogger struct {
+	next     Logger
+	maxBatch int
+	maxDelay time.Duration
+
+	m       sync.Mutex
+	pending []Event
+	timer   *time.Timer
+	// gen is incremented whenever a batch is emitted, so that a timer which fires concurrently with another flush
+	// does not emit the following batch early.
+	gen uint64
+
+	// flushing is set while FlushContext is forwarding events, or a chunk it gave up waiting for is still being
+	// logged. Batches aren't emitted meanwhile, so that they can't overtake the events being flushed.
+	flushing bool
+	// inflight is set while a chunk FlushContext gave up waiting for is still being logged, and closed once it has.
+	inflight chan struct{}
+
+	// flushM serializes flushes of the underlying Logger, whether manual or periodic. FlushContext releases it when it
+	// returns, even if a chunk it forwarded is still being logged, so that a stuck Logger can't block other flushes.
+	flushM        sync.Mutex
+	flushInterval time.Duration
+	stop          chan struct{}
+	stopped       chan struct{}
+	closeOnce     sync.Once
+}
+
+// A BatchOption configures a BatchLogger.
+type BatchOption func(*BatchLogger)
+
+// WithFlushInterval makes a BatchLogger flush every d, so events aren't held in a buffer during quiet periods. The
+// periodic flushing is stopped by Close.
+func WithFlushInterval(d time.Duration) BatchOption {
+	return func(b *BatchLogger) {
+		b.flushInterval = d
+	}
+}
+
+// NewBatchLogger creates a BatchLogger which forwards batches of events to next. A maxBatch of zero or less disables
+// the size threshold, and a maxDelay of zero or less disables the time threshold; if both are disabled, events are
+// only emitted on Flush.
+func NewBatchLogger(next Logger, maxBatch int, maxDelay time.Duration, opts ...BatchOption) *BatchLogger {
+	b := &BatchLogger{
+		next:     next,
+		maxBatch: maxBatch,
+		maxDelay: maxDelay,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.flushInterval > 0 {
+		b.stop = make(chan struct{})
+		b.stopped = make(chan struct{})
+		go b.flushPeriodically()
+	}
+	return b
+}
+
+// Log queues the events, emitting a batch if the size threshold has been reached.
+func (b *BatchLogger) Log(evs ...Event) {
+	if len(evs) == 0 {
+		return
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if len(b.pending) == 0 {
+		b.startTimerLocked()
+	}
+	b.pending = append(b.pending, evs...)
+
+	if b.maxBatch > 0 && len(b.pending) >= b.maxBatch {
+		b.emitLocked()
+	}
+}
+
+// Flush immediately emits any pending batch, and then flushes the underlying Logger. If a chunk which FlushContext gave
+// up waiting for is still being logged, the pending batch is held back until it has been, rather than overtaking it,
+// and Flush doesn't wait for it.
+func (b *BatchLogger) Flush() error {
+	b.flushM.Lock()
+	defer b.flushM.Unlock()
+	b.m.Lock()
+	b.emitLocked()
+	b.m.Unlock()
+	return b.next.Flush()
+}
+
+// FlushContext is like Flush, but gives up when the context is done, so that flushing during shutdown is bounded
+// even if the underlying Logger is stuck. Pending events are forwarded in chunks of at most the maximum batch size,
+// and the context is checked between chunks: if it is done, the remaining events are put back to be emitted later,
+// and the context's error is returned. A single chunk, or the final flush of the underlying Logger, can't be
+// interrupted, but FlushContext returns without waiting for it. Events are still forwarded in order: until that chunk
+// has been logged, no other batch is emitted, and a further FlushContext waits for it (until its own context is done).
+func (b *BatchLogger) FlushContext(ctx context.Context) error {
+	b.flushM.Lock()
+	defer b.flushM.Unlock()
+	b.m.Lock()
+	if inflight := b.inflight; inflight != nil {
+		// The chunk an earlier call gave up waiting for must be logged first. No other call can start logging a chunk
+		// meanwhile, as this one holds flushM.
+		b.m.Unlock()
+		select {
+		case <-inflight:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		b.m.Lock()
+	}
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.gen++
+	b.flushing = true
+	b.m.Unlock()
+
+	chunk := b.maxBatch
+	if chunk <= 0 {
+		chunk = flushChunkSize
+	}
+	for len(batch) > 0 {
+		if err := ctx.Err(); err != nil {
+			b.finishFlush(batch)
+			return err
+		}
+		n := chunk
+		if n > len(batch) {
+			n = len(batch)
+		}
+		done := make(chan struct{})
+		go func(evs []Event) {
+			defer close(done)
+			b.next.Log(evs...)
+		}(batch[:n])
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// The flush only finishes once the chunk has been logged
+			b.m.Lock()
+			b.inflight = make(chan struct{})
+			b.m.Unlock()
+			go func(rest []Event) {
+				<-done
+				b.finishFlush(rest)
+			}(batch[n:])
+			return ctx.Err()
+		}
+		batch = batch[n:]
+	}
+	err := FlushWithContext(ctx, b.next)
+	b.finishFlush(nil)
+	return err
+}
+
+// finishFlush ends a flush started by FlushContext, putting the events which weren't forwarded back at the front of
+// the pending batch. Events logged while flushing are emitted if the size threshold has been reached, and otherwise
+// wait for the timer.
+func (b *BatchLogger) finishFlush(unsent []Event) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.flushing = false
+	if b.inflight != nil {
+		close(b.inflight)
+		b.inflight = nil
+	}
+	if len(unsent) > 0 {
+		b.pending = append(append([]Event(nil), unsent...), b.pending...)
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+	if b.maxBatch > 0 && len(b.pending) >= b.maxBatch {
+		b.emitLocked()
+		return
+	}
+	// Any timer which fired while flushing couldn't emit, so it is restarted
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.gen++
+	b.startTimerLocked()
+}
+
+// Close stops periodic flushing, if it was enabled with WithFlushInterval, and then flushes any pending events, as by
+// Flush.
+func (b *BatchLogger) Close() error {
+	b.closeOnce.Do(func() {
+		if b.stop != nil {
+			close(b.stop)
+			<-b.stopped
+		}
+	})
+	return b.Flush()
+}
+
+func (b *BatchLogger) flushPeriodically() {
+	defer close(b.stopped)
+	t := time.NewTicker(b.flushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			// There's nowhere to report an error from a periodic flush
+			_ = b.Flush()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+func (b *BatchLogger) timerFlush(gen uint64) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if gen != b.gen {
+		// The batch this timer was started for has already been emitted
+		return
+	}
+	b.emitLocked()
+}
+
+// startTimerLocked starts the timer which emits the pending batch after maxDelay, if there is one. The caller must
+// hold b.m.
+func (b *BatchLogger) startTimerLocked() {
+	if b.maxDelay <= 0 {
+		return
+	}
+	gen := b.gen
+	b.timer = time.AfterFunc(b.maxDelay, func() {
+		b.timerFlush(gen)
+	})
+}
+
+// emitLocked sends the pending batch to the underlying Logger, unless FlushContext is flushing. The caller must hold
+// b.m.
+func (b *BatchLogger) emitLocked() {
+	if b.flushing {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.gen++
+	if len(b.pending) == 0 {
+		return
+	}
+
+	batch := b.pending
+	b.pending = nil
+	b.next.Log(batch...)
+}
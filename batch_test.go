@@ -0,0 +1,156 @@
+package slog
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchLoggerSizeThreshold(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewBatchLogger(next, 3, time.Hour)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	logger.Log(Eventf(InfoSeverity, context.Background(), "two"))
+	assert.Empty(t, next.Events())
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "three"))
+	events := next.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "one", events[0].Message)
+	assert.Equal(t, "three", events[2].Message)
+}
+
+func TestBatchLoggerTimeThreshold(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewBatchLogger(next, 100, 10*time.Millisecond)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	waitFor(t, func() bool {
+		return len(next.Events()) == 1
+	})
+
+	// The timer should be re-armed for the next batch
+	logger.Log(Eventf(InfoSeverity, context.Background(), "two"))
+	waitFor(t, func() bool {
+		return len(next.Events()) == 2
+	})
+}
+
+func TestBatchLoggerFlush(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewBatchLogger(next, 100, time.Hour)
+
+	require.NoError(t, logger.Flush())
+	assert.Empty(t, next.Events())
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	require.NoError(t, logger.Flush())
+	assert.Len(t, next.Events(), 1)
+}
+
+func TestBatchLoggerFlushInterval(t *testing.T) {
+	next := &countingFlushLogger{InMemoryLogger: NewInMemoryLogger()}
+	logger := NewBatchLogger(next, 0, 0, WithFlushInterval(5*time.Millisecond))
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	waitFor(t, func() bool {
+		return next.Len() == 1
+	})
+
+	require.NoError(t, logger.Close())
+	require.NoError(t, logger.Close())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "two"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, next.Len())
+}
+
+// gatedLogger logs each batch of events once it can take a token from gate.
+type gatedLogger struct {
+	*InMemoryLogger
+	gate chan struct{}
+}
+
+func (l *gatedLogger) Log(evs ...Event) {
+	<-l.gate
+	l.InMemoryLogger.Log(evs...)
+}
+
+func TestBatchLoggerFlushContext(t *testing.T) {
+	next := &gatedLogger{InMemoryLogger: NewInMemoryLogger(), gate: make(chan struct{}, 1)}
+	logger := NewBatchLogger(next, 0, 0)
+	expected := make([]string, 2*flushChunkSize+flushChunkSize/2)
+	for i := range expected {
+		expected[i] = strconv.Itoa(i)
+		logger.Log(Eventf(InfoSeverity, context.Background(), expected[i]))
+	}
+
+	// The first chunk is forwarded, and the deadline passes while the second is stuck
+	next.gate <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := logger.FlushContext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, ctx.Err(), err)
+	assert.Equal(t, expected[:flushChunkSize], messages(next.Events()))
+
+	// Events logged meanwhile don't overtake the stuck chunk, and flushes don't wait for it forever
+	expected = append(expected, "late")
+	logger.Log(Eventf(InfoSeverity, context.Background(), "late"))
+	closed := make(chan error, 1)
+	go func() {
+		closed <- logger.Close()
+	}()
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for the stuck chunk")
+	}
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	assert.Equal(t, context.DeadlineExceeded, logger.FlushContext(ctx2))
+	assert.Equal(t, expected[:flushChunkSize], messages(next.Events()))
+
+	// Once the sink recovers, the remaining events are flushed exactly once, in order
+	close(next.gate)
+	require.NoError(t, logger.FlushContext(context.Background()))
+	assert.Equal(t, expected, messages(next.Events()))
+}
+
+func TestBatchLoggerFlushContextHoldsBatches(t *testing.T) {
+	next := &gatedLogger{InMemoryLogger: NewInMemoryLogger(), gate: make(chan struct{})}
+	logger := NewBatchLogger(next, 2, 0)
+	logger.Log(Eventf(InfoSeverity, context.Background(), "1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, logger.FlushContext(ctx))
+
+	// A full batch isn't emitted while the flushed chunk is stuck, but is once it has been logged
+	logger.Log(Eventf(InfoSeverity, context.Background(), "2"), Eventf(InfoSeverity, context.Background(), "3"))
+	assert.Empty(t, next.Events())
+	close(next.gate)
+	waitFor(t, func() bool {
+		return next.Len() == 3
+	})
+	assert.Equal(t, []string{"1", "2", "3"}, messages(next.Events()))
+}
+
+// waitFor polls until the condition is true, failing the test if it isn't within a second. Unlike assert.Eventually,
+// the condition is evaluated on the test goroutine, so it never runs concurrently with the rest of the test or after
+// it has finished.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,136 @@
+package slog
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by CircuitBreakerLogger.Flush while the circuit is open.
+var ErrCircuitOpen = errors.New("slog: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreakerLogger.
+type CircuitState int
+
+const (
+	// CircuitClosed means events are forwarded as normal.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means events are being dropped.
+	CircuitOpen
+	// CircuitHalfOpen means the cooldown has elapsed, and the next operation will probe the underlying logger.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "OPEN"
+	case CircuitHalfOpen:
+		return "HALF_OPEN"
+	default:
+		return "CLOSED"
+	}
+}
+
+// A CircuitBreakerLogger protects callers from a failing Logger. After a number of consecutive failures the circuit
+// opens, and events are dropped rather than forwarded until a cooldown has elapsed. The next operation then probes
+// the underlying logger: if it succeeds the circuit closes, otherwise it opens again.
+//
+// A Flush which returns an error is a failure. As Log can't return an error, a Log is only considered to have failed
+// if the underlying logger has a LastError method (like WriterLogger) which returns an error afterwards.
+type CircuitBreakerLogger struct {
+	next             Logger
+	failureThreshold int
+	cooldown         time.Duration
+
+	m        sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	dropped  uint64
+}
+
+// NewCircuitBreakerLogger creates a CircuitBreakerLogger which opens after failureThreshold consecutive failures of
+// next, and stays open for cooldown.
+func NewCircuitBreakerLogger(next Logger, failureThreshold int, cooldown time.Duration) *CircuitBreakerLogger {
+	return &CircuitBreakerLogger{
+		next:             next,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+}
+
+// Log forwards the events to the underlying logger, or drops them if the circuit is open.
+func (l *CircuitBreakerLogger) Log(evs ...Event) {
+	if !l.allow() {
+		l.m.Lock()
+		l.dropped += uint64(len(evs))
+		l.m.Unlock()
+		return
+	}
+
+	l.next.Log(evs...)
+	var err error
+	if le, ok := l.next.(interface{ LastError() error }); ok {
+		err = le.LastError()
+	}
+	l.record(err)
+}
+
+// Flush the underlying logger, unless the circuit is open.
+func (l *CircuitBreakerLogger) Flush() error {
+	if !l.allow() {
+		return ErrCircuitOpen
+	}
+	err := l.next.Flush()
+	l.record(err)
+	return err
+}
+
+// State returns the current state of the circuit.
+func (l *CircuitBreakerLogger) State() CircuitState {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.stateLocked()
+}
+
+// Dropped returns the number of events which have been dropped while the circuit was open.
+func (l *CircuitBreakerLogger) Dropped() uint64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.dropped
+}
+
+func (l *CircuitBreakerLogger) stateLocked() CircuitState {
+	switch {
+	case !l.open:
+		return CircuitClosed
+	case now().Sub(l.openedAt) < l.cooldown:
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
+
+// allow reports whether an operation should be forwarded to the underlying logger.
+func (l *CircuitBreakerLogger) allow() bool {
+	return l.State() != CircuitOpen
+}
+
+// record updates the circuit with the result of an operation.
+func (l *CircuitBreakerLogger) record(err error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if err == nil {
+		l.failures = 0
+		l.open = false
+		return
+	}
+
+	l.failures++
+	// A failed probe re-opens the circuit immediately
+	if l.open || l.failures >= l.failureThreshold {
+		l.open = true
+		l.openedAt = now()
+	}
+}
@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerLogger(t *testing.T) {
+	current := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return current
+	})
+	defer ResetClock()
+
+	w := &failingWriter{err: errors.New("broken")}
+	logger := NewCircuitBreakerLogger(NewWriterLogger(w), 2, time.Minute)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	assert.Equal(t, CircuitClosed, logger.State())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "two"))
+	assert.Equal(t, CircuitOpen, logger.State())
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "dropped"))
+	assert.Equal(t, uint64(1), logger.Dropped())
+	assert.Equal(t, ErrCircuitOpen, logger.Flush())
+
+	// A failed probe re-opens the circuit
+	current = current.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, logger.State())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "probe"))
+	assert.Equal(t, CircuitOpen, logger.State())
+
+	// A successful probe closes it
+	current = current.Add(time.Minute)
+	w.err = nil
+	logger.Log(Eventf(InfoSeverity, context.Background(), "probe"))
+	assert.Equal(t, CircuitClosed, logger.State())
+}
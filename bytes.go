@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// A BytesEncoding is a way of rendering []byte metadata values as strings.
+type BytesEncoding int32
+
+const (
+	// HexBytesEncoding renders byte slices as lowercase hexadecimal. This is the default.
+	HexBytesEncoding BytesEncoding = iota
+	// Base64BytesEncoding renders byte slices as standard base64.
+	Base64BytesEncoding
+)
+
+var bytesEncoding int32
+
+// SetBytesEncoding configures how []byte metadata values are rendered, both when events are serialized to JSON and
+// by Event.String. Otherwise, they would be formatted as a list of numbers.
+func SetBytesEncoding(enc BytesEncoding) {
+	atomic.StoreInt32(&bytesEncoding, int32(enc))
+}
+
+// encodeBytes renders b with the configured BytesEncoding.
+func encodeBytes(b []byte) string {
+	if BytesEncoding(atomic.LoadInt32(&bytesEncoding)) == Base64BytesEncoding {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// encodeBytesMetadata returns the metadata with []byte values replaced by their encoding. The metadata is only copied
+// if it contains any.
+func encodeBytesMetadata(metadata map[string]interface{}) map[string]interface{} {
+	result, copied := metadata, false
+	for k, v := range metadata {
+		b, ok := v.([]byte)
+		if !ok {
+			continue
+		}
+		if !copied {
+			result, copied = mergeMetadata(nil, metadata), true
+		}
+		result[k] = encodeBytes(b)
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package slog
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesEncoding(t *testing.T) {
+	e := Eventf(InfoSeverity, context.Background(), "foo", map[string]interface{}{
+		"raw":    []byte("hi!"),
+		"nested": map[string]interface{}{"raw": []byte("hi!")},
+	})
+
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+	decoded := Event{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "686921", decoded.Metadata["raw"])
+	assert.Contains(t, e.String(), "raw:686921")
+	assert.Contains(t, e.String(), `{"raw":"686921"}`)
+	assert.Equal(t, []byte("hi!"), e.Metadata["raw"], "the event's own metadata must not be modified")
+
+	SetBytesEncoding(Base64BytesEncoding)
+	defer SetBytesEncoding(HexBytesEncoding)
+	b, err = json.Marshal(e)
+	require.NoError(t, err)
+	decoded = Event{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "aGkh", decoded.Metadata["raw"])
+	assert.Contains(t, e.String(), "raw:aGkh")
+}
@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// CallerMetadataKey is the metadata key for the name of the function which logged an event, added when
+// SetCaptureCaller is on.
+const CallerMetadataKey = "caller"
+
+// captureCaller is non-zero if Eventf should add the name of the calling function to event metadata.
+var captureCaller int32
+
+// slogFuncPrefix is the prefix of the names of this package's functions, which are skipped when finding the caller.
+var slogFuncPrefix = reflect.TypeOf(Event{}).PkgPath() + "."
+
+// SetCaptureCaller enables or disables adding the name of the function which logged each event to its metadata,
+// under CallerMetadataKey. This is useful for grouping events, and is much cheaper than capturing a stack trace, as
+// only the name of the function is looked up. The caller is the first function outside this package, so it is the
+// same whether the event is logged with Eventf, a package-level function like Info, or a Logger's leveled methods.
+func SetCaptureCaller(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&captureCaller, v)
+}
+
+// metadataFromCaller returns the name of the function which logged the event, if SetCaptureCaller is on.
+func metadataFromCaller() map[string]interface{} {
+	if atomic.LoadInt32(&captureCaller) == 0 {
+		return nil
+	}
+	if name := callerName(); name != "" {
+		return map[string]interface{}{
+			CallerMetadataKey: name,
+		}
+	}
+	return nil
+}
+
+// callerName returns the name of the first function on the stack outside this package. Frames are resolved with
+// runtime.CallersFrames rather than runtime.FuncForPC, which would misattribute inlined calls.
+func callerName() string {
+	var pcs [16]uintptr
+	// Skip runtime.Callers, callerName and metadataFromCaller
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		// The package's own tests are callers like any other
+		if !strings.HasPrefix(frame.Function, slogFuncPrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.Function
+		}
+		if !more {
+			return ""
+		}
+	}
+}
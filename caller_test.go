@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logWidgetLoaded(ctx context.Context) {
+	Info(ctx, "Loaded widget")
+}
+
+func TestCaptureCaller(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+	ctx := context.Background()
+
+	Info(ctx, "Not captured")
+	SetCaptureCaller(true)
+	defer SetCaptureCaller(false)
+
+	Info(ctx, "Package function")
+	Infot(ctx, "Template {{.id}}", map[string]interface{}{"id": "w1"})
+	SeverityLogger{logger}.Info(ctx, "Leveled method")
+	logWidgetLoaded(ctx)
+	Log(Eventf(InfoSeverity, ctx, "Eventf", map[string]string{
+		CallerMetadataKey: "overridden",
+	}))
+
+	events := logger.Events()
+	require.Len(t, events, 6)
+	assert.NotContains(t, events[0].Metadata, CallerMetadataKey)
+	assert.Equal(t, "github.com/monzo/slog.TestCaptureCaller", events[1].Metadata[CallerMetadataKey])
+	assert.Equal(t, "github.com/monzo/slog.TestCaptureCaller", events[2].Metadata[CallerMetadataKey])
+	assert.Equal(t, "github.com/monzo/slog.TestCaptureCaller", events[3].Metadata[CallerMetadataKey])
+	assert.Equal(t, "github.com/monzo/slog.logWidgetLoaded", events[4].Metadata[CallerMetadataKey])
+	assert.Equal(t, "overridden", events[5].Metadata[CallerMetadataKey])
+}
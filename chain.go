@@ -0,0 +1,18 @@
+package slog
+
+// Chain builds a pipeline of loggers ending in sink. Each middleware wraps a Logger, returning a Logger which
+// forwards events to it. The middlewares are applied so that the first listed is outermost: events logged to the
+// returned Logger pass through each middleware in the order given before reaching the sink. For example:
+//
+//	logger := slog.Chain(sink,
+//		func(next slog.Logger) slog.Logger { return slog.NewLevelFilterLogger(next, slog.InfoSeverity) },
+//		func(next slog.Logger) slog.Logger { return slog.NewTruncateLogger(next, 1024, 50) })
+//
+// filters events by severity before truncating them.
+func Chain(sink Logger, middlewares ...func(next Logger) Logger) Logger {
+	l := sink
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		l = middlewares[i](l)
+	}
+	return l
+}
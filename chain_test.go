@@ -0,0 +1,47 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	sink := NewInMemoryLogger()
+	var order []string
+	record := func(name string) func(Logger) Logger {
+		return func(next Logger) Logger {
+			return loggerFunc(func(evs ...Event) {
+				order = append(order, name)
+				next.Log(evs...)
+			})
+		}
+	}
+
+	logger := Chain(sink,
+		record("outer"),
+		func(next Logger) Logger { return NewLevelFilterLogger(next, InfoSeverity) },
+		record("inner"),
+		func(next Logger) Logger {
+			return NewFieldsLogger(next, map[string]string{"service": "widgets"}, nil)
+		})
+
+	logger.Log(Eventf(DebugSeverity, context.Background(), "debug"))
+	logger.Log(Eventf(InfoSeverity, context.Background(), "info"))
+
+	assert.Equal(t, []string{"outer", "outer", "inner"}, order)
+	events := sink.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "info", events[0].Message)
+	assert.Equal(t, map[string]string{"service": "widgets"}, events[0].Labels)
+
+	assert.Equal(t, sink, Chain(sink))
+}
+
+type loggerFunc func(evs ...Event)
+
+func (f loggerFunc) Log(evs ...Event) { f(evs...) }
+
+func (f loggerFunc) Flush() error { return nil }
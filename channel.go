@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// channelItem is either a line to be written by a ChannelJSONLogger, or a request to flush, which is acknowledged by
+// closing flushed once every line queued before it has been written.
+type channelItem struct {
+	line    []byte
+	flushed chan struct{}
+}
+
+// A ChannelJSONLogger writes each event as a line of JSON to an io.Writer. Unlike loggers which hold a mutex while
+// writing, events are marshalled by the goroutine which logs them, and the result handed over a channel to a single
+// goroutine which does all the writing, so that goroutines logging concurrently don't contend for a lock.
+type ChannelJSONLogger struct {
+	writeErrors
+	w         io.Writer
+	items     chan channelItem
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewChannelJSONLogger creates a ChannelJSONLogger which writes to w, and starts its writing goroutine. Up to queue
+// events can be waiting to be written before Log blocks. The goroutine runs until Close is called.
+func NewChannelJSONLogger(w io.Writer, queue int) *ChannelJSONLogger {
+	l := &ChannelJSONLogger{
+		w:       w,
+		items:   make(chan channelItem, queue),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go l.write()
+	return l
+}
+
+// Log marshals the events and queues them to be written. Any error is reported by the next call to Flush. Events
+// logged after Close are dropped.
+func (l *ChannelJSONLogger) Log(evs ...Event) {
+	for _, e := range evs {
+		b, err := json.Marshal(e)
+		if err != nil {
+			l.record(err)
+			continue
+		}
+		select {
+		case l.items <- channelItem{line: append(b, '\n')}:
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// Flush waits until every event already logged has been written, flushes the underlying writer if it supports
+// flushing, and returns the first error encountered since the last Flush.
+func (l *ChannelJSONLogger) Flush() error {
+	flushed := make(chan struct{})
+	select {
+	case l.items <- channelItem{flushed: flushed}:
+		select {
+		case <-flushed:
+		case <-l.stopped:
+		}
+	case <-l.done:
+	}
+	return l.takeError()
+}
+
+// Close flushes the logger, and then stops its writing goroutine.
+func (l *ChannelJSONLogger) Close() error {
+	err := l.Flush()
+	l.closeOnce.Do(func() {
+		close(l.done)
+		<-l.stopped
+	})
+	return err
+}
+
+func (l *ChannelJSONLogger) write() {
+	defer close(l.stopped)
+	for {
+		select {
+		case item := <-l.items:
+			if item.flushed != nil {
+				if f, ok := l.w.(interface{ Flush() error }); ok {
+					l.record(f.Flush())
+				}
+				close(item.flushed)
+				continue
+			}
+			_, err := l.w.Write(item.line)
+			l.record(err)
+		case <-l.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package slog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelJSONLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewChannelJSONLogger(buf, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger.Log(Eventf(InfoSeverity, context.Background(), "event"+strconv.Itoa(i)))
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(t, logger.Flush())
+
+	messages := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	for scanner.Scan() {
+		e := Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		assert.Equal(t, InfoSeverity, e.Severity)
+		messages[e.Message] = true
+	}
+	assert.Len(t, messages, 10)
+
+	require.NoError(t, logger.Close())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "dropped"))
+	assert.NoError(t, logger.Flush())
+	assert.NotContains(t, buf.String(), "dropped")
+}
+
+func TestChannelJSONLoggerWriteError(t *testing.T) {
+	diskFull := errors.New("disk full")
+	w := &failingWriter{err: diskFull}
+	logger := NewChannelJSONLogger(w, 0)
+	defer logger.Close()
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "foo"))
+	assert.Equal(t, diskFull, logger.Flush())
+	assert.Equal(t, diskFull, logger.LastError())
+
+	w.err = nil
+	logger.Log(Eventf(InfoSeverity, context.Background(), "foo"))
+	assert.NoError(t, logger.Flush())
+	assert.NoError(t, logger.LastError())
+}
+
+// mutexJSONLogger is the conventional alternative to ChannelJSONLogger, which marshals and writes each event while
+// holding a mutex.
+type mutexJSONLogger struct {
+	m sync.Mutex
+	w io.Writer
+}
+
+func (l *mutexJSONLogger) Log(evs ...Event) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, e := range evs {
+		b, err := json.Marshal(e)
+		if err == nil {
+			l.w.Write(append(b, '\n'))
+		}
+	}
+}
+
+func (l *mutexJSONLogger) Flush() error {
+	return nil
+}
+
+func benchmarkJSONLogger(b *testing.B, logger Logger) {
+	e := Eventf(InfoSeverity, context.Background(), "Loaded widget", map[string]interface{}{
+		"widget_id": "w1",
+		"count":     42,
+	})
+	b.ReportAllocs()
+	b.SetParallelism(16)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Log(e)
+		}
+	})
+	logger.Flush()
+}
+
+func BenchmarkMutexJSONLogger(b *testing.B) {
+	benchmarkJSONLogger(b, &mutexJSONLogger{w: ioutil.Discard})
+}
+
+func BenchmarkChannelJSONLogger(b *testing.B) {
+	logger := NewChannelJSONLogger(ioutil.Discard, 1024)
+	defer logger.Close()
+	benchmarkJSONLogger(b, logger)
+}
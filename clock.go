@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sequence is the Sequence of the most recently built event.
+var sequence uint64
+
+var (
+	clock    = time.Now
+	location = time.UTC
+	clockM   sync.RWMutex
+)
+
+// SetClock replaces the function used to timestamp events. This is intended for tests which need deterministic
+// timestamps.
+func SetClock(c func() time.Time) {
+	clockM.Lock()
+	defer clockM.Unlock()
+	clock = c
+}
+
+// ResetClock restores the default clock, time.Now.
+func ResetClock() {
+	SetClock(time.Now)
+}
+
+func now() time.Time {
+	clockM.RLock()
+	defer clockM.RUnlock()
+	return clock()
+}
+
+// SetTimezone sets the location in which event timestamps are expressed, and so rendered by Event.String. By default,
+// and if loc is nil, timestamps are in UTC.
+func SetTimezone(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	clockM.Lock()
+	defer clockM.Unlock()
+	location = loc
+}
+
+// eventTime returns the current time in the configured location, for timestamping events.
+func eventTime() time.Time {
+	clockM.RLock()
+	defer clockM.RUnlock()
+	return clock().In(location)
+}
+
+// inEventLocation returns t in the configured location, for timestamping events with a given time.
+func inEventLocation(t time.Time) time.Time {
+	clockM.RLock()
+	defer clockM.RUnlock()
+	return t.In(location)
+}
+
+// nextSequence returns the Sequence for a new event.
+func nextSequence() uint64 {
+	return atomic.AddUint64(&sequence, 1)
+}
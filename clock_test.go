@@ -0,0 +1,99 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return fixed
+	})
+	defer ResetClock()
+
+	e := Eventf(InfoSeverity, context.Background(), "foo")
+	assert.Equal(t, fixed, e.Timestamp)
+}
+
+func TestSetTimezone(t *testing.T) {
+	fixed := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return fixed
+	})
+	defer ResetClock()
+	loc := time.FixedZone("EST", -5*60*60)
+	SetTimezone(loc)
+	defer SetTimezone(nil)
+
+	e := Eventf(InfoSeverity, context.Background(), "foo")
+	assert.True(t, fixed.Equal(e.Timestamp))
+	assert.Equal(t, loc, e.Timestamp.Location())
+	assert.Contains(t, e.String(), "[2020-01-01 22:04:05-0500 (EST)]")
+
+	SetTimezone(nil)
+	e = Eventf(InfoSeverity, context.Background(), "foo")
+	assert.Equal(t, time.UTC, e.Timestamp.Location())
+}
+
+func TestEventSequence(t *testing.T) {
+	const goroutines, events = 8, 100
+	sequences := make([][]uint64, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < events; j++ {
+				sequences[i] = append(sequences[i], Eventf(InfoSeverity, context.Background(), "foo").Sequence)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[uint64]bool{}
+	for _, seqs := range sequences {
+		for j, seq := range seqs {
+			assert.False(t, seen[seq], "sequence %d assigned twice", seq)
+			seen[seq] = true
+			if j > 0 {
+				assert.Greater(t, seq, seqs[j-1])
+			}
+		}
+	}
+	assert.Len(t, seen, goroutines*events)
+
+	// The sequence is independent of the clock
+	fixed := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return fixed
+	})
+	defer ResetClock()
+	a, b := Eventf(InfoSeverity, context.Background(), "a"), Eventf(InfoSeverity, context.Background(), "b")
+	assert.Equal(t, a.Timestamp, b.Timestamp)
+	assert.Equal(t, a.Sequence+1, b.Sequence)
+}
+
+func TestEventfAt(t *testing.T) {
+	ts := time.Date(2019, 6, 7, 8, 9, 10, 11, time.FixedZone("BST", 60*60))
+	SetTimezone(time.FixedZone("EST", -5*60*60))
+	defer SetTimezone(nil)
+
+	e := EventfAt(ts, WarnSeverity, context.Background(), "Replayed %s", "widget", map[string]interface{}{"widget_id": "w1"})
+	assert.True(t, ts.Equal(e.Timestamp))
+	assert.Equal(t, "EST", e.Timestamp.Location().String())
+	assert.Equal(t, WarnSeverity, e.Severity)
+	assert.Equal(t, "Replayed widget", e.Message)
+	assert.Equal(t, "w1", e.Metadata["widget_id"])
+	assert.NotZero(t, e.Sequence)
+	assert.NotEmpty(t, e.Id)
+
+	// A zero timestamp means now
+	before := time.Now()
+	e = EventfAt(time.Time{}, InfoSeverity, context.Background(), "foo")
+	assert.False(t, e.Timestamp.Before(before))
+}
@@ -0,0 +1,352 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultParamsNamespace is the namespace used by WithParams and Params.
+const DefaultParamsNamespace = ""
+
+const (
+	// ContextErrorMetadataKey is the metadata key for the context's error, added when SetAnnotateContextState is on.
+	ContextErrorMetadataKey = "ctx_error"
+	// ContextDeadlineMetadataKey is the metadata key for the number of milliseconds until the context's deadline
+	// (negative if it has passed), added when SetAnnotateContextState is on.
+	ContextDeadlineMetadataKey = "ctx_deadline_in_ms"
+)
+
+// DefaultMaxParamsDepth is the default limit set by SetMaxParamsDepth.
+const DefaultMaxParamsDepth = 64
+
+// maxParamsDepth is the number of layers of params a context can hold before they are compacted.
+var maxParamsDepth int32 = DefaultMaxParamsDepth
+
+// annotateContextState is non-zero if Eventf should add the state of the context to event metadata.
+var annotateContextState int32
+
+var (
+	metadataParamsNamespaces  = []string{DefaultParamsNamespace}
+	metadataParamsNamespacesM sync.RWMutex
+)
+
+type paramsKey struct {
+	namespace string
+}
+
+// paramsLayer is a set of params added by a single call to WithNamespacedParams. Layers are only merged when they
+// are first read, so adding params to a context is cheap; the merged result is then cached.
+type paramsLayer struct {
+	parent *paramsLayer
+	params map[string]string
+	// depth is the number of layers in the chain ending with this one.
+	depth int
+
+	mergeOnce sync.Once
+	merged    map[string]string
+}
+
+// all returns the params of this layer merged with those of its ancestors, with newer values taking precedence. The
+// returned map is shared, so must not be modified.
+func (l *paramsLayer) all() map[string]string {
+	if l == nil {
+		return nil
+	}
+	l.mergeOnce.Do(func() {
+		l.merged = mergeLabels(mergeLabels(nil, l.params), l.parent.all())
+	})
+	return l.merged
+}
+
+type typedParamsKey struct{}
+
+// typedParamsLayer is like paramsLayer, but for params added by WithTypedParams.
+type typedParamsLayer struct {
+	parent *typedParamsLayer
+	params map[string]interface{}
+}
+
+// WithParams returns a copy of the parent context containing the given log parameters. Events logged with the
+// returned context include these parameters as metadata. If the parent already contains parameters, they are merged,
+// with the new values taking precedence. Parameters with an empty key are never intentional, so are dropped.
+//
+// The parent context is never modified: contexts derived from a common parent (such as those of goroutines in an
+// errgroup) share the parent's parameters, but never observe each other's additions. It is safe to do this
+// concurrently.
+func WithParams(ctx context.Context, params map[string]string) context.Context {
+	return WithNamespacedParams(ctx, DefaultParamsNamespace, params)
+}
+
+// WithParamsKV is like WithParams, but takes the parameters as alternating keys and values. It panics if given an
+// odd number of arguments.
+func WithParamsKV(ctx context.Context, kv ...string) context.Context {
+	if len(kv)%2 != 0 {
+		panic("slog: WithParamsKV called with an odd number of arguments")
+	}
+
+	params := make(map[string]string, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		params[kv[i]] = kv[i+1]
+	}
+	// The map is freshly built, so doesn't need to be copied
+	return withParamsLayer(ctx, DefaultParamsNamespace, params)
+}
+
+// WithParamMaps is like WithParams, but takes several maps of parameters, such as from different sources, which are
+// merged in order, with later maps taking precedence. This adds a single layer of parameters to the context, so is
+// cheaper than calling WithParams with each map, or merging them first.
+func WithParamMaps(ctx context.Context, maps ...map[string]string) context.Context {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	if size == 0 {
+		return withParamsLayer(ctx, DefaultParamsNamespace, nil)
+	}
+
+	params := make(map[string]string, size)
+	for _, m := range maps {
+		for k, v := range m {
+			params[k] = v
+		}
+	}
+	// The map is freshly built, so doesn't need to be copied
+	return withParamsLayer(ctx, DefaultParamsNamespace, params)
+}
+
+// WithFreshParams is like WithParams, but discards any parameters of the parent context rather than merging with
+// them, so that a distinct operation started from within another (such as a background job triggered by a request)
+// isn't logged with unrelated inherited parameters. Only params in DefaultParamsNamespace are discarded; other
+// namespaces and typed params are unaffected. An empty map leaves the context with no parameters.
+func WithFreshParams(ctx context.Context, params map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	params = mergeLabels(nil, params)
+	delete(params, "")
+	return context.WithValue(ctx, paramsKey{DefaultParamsNamespace}, &paramsLayer{
+		params: params,
+		depth:  1,
+	})
+}
+
+// Params returns the log parameters stored in the context by WithParams.
+func Params(ctx context.Context) map[string]string {
+	return NamespacedParams(ctx, DefaultParamsNamespace)
+}
+
+// ParamsReadOnly is like Params, but returns the context's params without copying them. The returned map is shared
+// with the context, so it must not be mutated; it is intended for hot paths which only read the params.
+func ParamsReadOnly(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	layer, _ := ctx.Value(paramsKey{DefaultParamsNamespace}).(*paramsLayer)
+	return layer.all()
+}
+
+// RangeParams calls fn for each of the log parameters stored in the context by WithParams, until fn returns false.
+// Unlike Params, it doesn't build a map of the parameters, so is suitable for hot paths which only iterate them. The
+// order of iteration is unspecified.
+func RangeParams(ctx context.Context, fn func(key, value string) bool) {
+	if ctx == nil {
+		return
+	}
+	top, _ := ctx.Value(paramsKey{DefaultParamsNamespace}).(*paramsLayer)
+	for layer := top; layer != nil; layer = layer.parent {
+		for k, v := range layer.params {
+			if top.shadows(layer, k) {
+				continue
+			}
+			if !fn(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// shadows reports whether any layer from l up to (but excluding) its ancestor contains the key.
+func (l *paramsLayer) shadows(ancestor *paramsLayer, key string) bool {
+	for ; l != ancestor; l = l.parent {
+		if _, ok := l.params[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithNamespacedParams is like WithParams, but stores the parameters in a separate namespace. Parameters in different
+// namespaces do not interact, and only the namespaces configured with SetMetadataParamsNamespaces (by default, just
+// DefaultParamsNamespace) are included in event metadata.
+func WithNamespacedParams(ctx context.Context, namespace string, params map[string]string) context.Context {
+	return withParamsLayer(ctx, namespace, mergeLabels(nil, params))
+}
+
+// withParamsLayer adds a layer of params to the context. The params map is retained (and params with empty keys are
+// removed from it), so must not be used by the caller afterwards.
+func withParamsLayer(ctx context.Context, namespace string, params map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	delete(params, "")
+	if len(params) == 0 {
+		return ctx
+	}
+
+	key := paramsKey{namespace}
+	parent, _ := ctx.Value(key).(*paramsLayer)
+	layer := &paramsLayer{
+		parent: parent,
+		params: params,
+		depth:  1,
+	}
+	if parent != nil {
+		layer.depth = parent.depth + 1
+		// Reading params from a long chain is slow, so past the limit it is compacted into a single layer
+		if max := int(atomic.LoadInt32(&maxParamsDepth)); max > 0 && layer.depth > max {
+			layer.params = mergeLabels(params, parent.all())
+			layer.parent = nil
+			layer.depth = 1
+		}
+	}
+	return context.WithValue(ctx, key, layer)
+}
+
+// SetMaxParamsDepth sets the number of layers of params, each added by a call to WithParams or similar, which a
+// context can hold before they are compacted into one. Long-lived contexts to which params are repeatedly added would
+// otherwise build up a chain of layers which is slow to read. The default is DefaultMaxParamsDepth; 0 disables
+// compaction.
+func SetMaxParamsDepth(n int) {
+	atomic.StoreInt32(&maxParamsDepth, int32(n))
+}
+
+// NamespacedParams returns the log parameters stored in the given namespace of the context.
+func NamespacedParams(ctx context.Context, namespace string) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	layer, _ := ctx.Value(paramsKey{namespace}).(*paramsLayer)
+	return mergeLabels(nil, layer.all())
+}
+
+// WithTypedParams returns a copy of the parent context containing the given log parameters. Unlike WithParams, the
+// values keep their types when they are included in event metadata. If the parent already contains typed parameters,
+// they are merged, with the new values taking precedence.
+//
+// Typed parameters are always included in event metadata, and take precedence over string parameters with the same
+// key. As with WithParams, parameters with an empty key are dropped.
+func WithTypedParams(ctx context.Context, params map[string]interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(params) == 0 {
+		return ctx
+	}
+
+	params = mergeMetadata(nil, params)
+	delete(params, "")
+	if len(params) == 0 {
+		return ctx
+	}
+
+	parent, _ := ctx.Value(typedParamsKey{}).(*typedParamsLayer)
+	return context.WithValue(ctx, typedParamsKey{}, &typedParamsLayer{
+		parent: parent,
+		params: params,
+	})
+}
+
+// TypedParams returns the log parameters stored in the context by WithTypedParams.
+func TypedParams(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	layer, _ := ctx.Value(typedParamsKey{}).(*typedParamsLayer)
+
+	result := map[string]interface{}(nil)
+	for ; layer != nil; layer = layer.parent {
+		result = mergeMetadata(result, layer.params)
+	}
+	return result
+}
+
+// CopyParams returns a copy of dst containing the log parameters of src: its params in DefaultParamsNamespace and any
+// namespace configured with SetMetadataParamsNamespaces, and its typed params. Where dst already contains a parameter,
+// the value from src takes precedence.
+//
+// This allows work detached from a request, such as a background goroutine, to log with the request's parameters
+// without retaining its cancellable context.
+func CopyParams(dst, src context.Context) context.Context {
+	if dst == nil {
+		dst = context.Background()
+	}
+	if src == nil {
+		return dst
+	}
+
+	metadataParamsNamespacesM.RLock()
+	namespaces := metadataParamsNamespaces
+	metadataParamsNamespacesM.RUnlock()
+
+	dst = withParamsLayer(dst, DefaultParamsNamespace, NamespacedParams(src, DefaultParamsNamespace))
+	for _, namespace := range namespaces {
+		if namespace != DefaultParamsNamespace {
+			dst = withParamsLayer(dst, namespace, NamespacedParams(src, namespace))
+		}
+	}
+	return WithTypedParams(dst, TypedParams(src))
+}
+
+// SetMetadataParamsNamespaces configures which params namespaces are included in the metadata of events. Where a key
+// is present in multiple namespaces, the namespace listed first wins.
+func SetMetadataParamsNamespaces(namespaces ...string) {
+	metadataParamsNamespacesM.Lock()
+	defer metadataParamsNamespacesM.Unlock()
+	metadataParamsNamespaces = namespaces
+}
+
+// metadataFromContext returns the params from the context which should be included in event metadata.
+func metadataFromContext(ctx context.Context) map[string]interface{} {
+	metadataParamsNamespacesM.RLock()
+	namespaces := metadataParamsNamespaces
+	metadataParamsNamespacesM.RUnlock()
+
+	result := TypedParams(ctx)
+	for _, namespace := range namespaces {
+		result = mergeMetadata(result, stringMapToInterfaceMap(NamespacedParams(ctx, namespace)))
+	}
+	return result
+}
+
+// SetAnnotateContextState enables or disables annotating events with the state of their context. When enabled, events
+// logged with a cancelled context include its error, and events logged with a context that has a deadline include the
+// time remaining until it. This is disabled by default.
+func SetAnnotateContextState(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&annotateContextState, v)
+}
+
+// metadataFromContextState returns metadata describing the cancellation state of the context, if
+// SetAnnotateContextState is enabled.
+func metadataFromContextState(ctx context.Context) map[string]interface{} {
+	if atomic.LoadInt32(&annotateContextState) == 0 || ctx == nil {
+		return nil
+	}
+
+	result := map[string]interface{}(nil)
+	if err := ctx.Err(); err != nil {
+		result = mergeMetadata(result, map[string]interface{}{
+			ContextErrorMetadataKey: err.Error(),
+		})
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		result = mergeMetadata(result, map[string]interface{}{
+			ContextDeadlineMetadataKey: deadline.Sub(now()).Milliseconds(),
+		})
+	}
+	return result
+}
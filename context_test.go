@@ -0,0 +1,497 @@
+package slog
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	})
+	ctx = WithParams(ctx, map[string]string{
+		"foo": "overwritten",
+	})
+
+	assert.Equal(t, map[string]string{
+		"foo": "overwritten",
+		"baz": "qux",
+	}, Params(ctx))
+	assert.Nil(t, Params(context.Background()))
+}
+
+func TestWithParamsDropsEmptyKeys(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"":    "value",
+		"foo": "bar",
+	})
+	ctx = WithParamsKV(ctx, "", "kv", "baz", "qux")
+	ctx = WithTypedParams(ctx, map[string]interface{}{
+		"":      1,
+		"count": 2,
+	})
+
+	assert.Equal(t, map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	}, Params(ctx))
+	assert.Equal(t, map[string]interface{}{"count": 2}, TypedParams(ctx))
+
+	onlyEmpty := WithParams(context.Background(), map[string]string{"": "value"})
+	assert.Equal(t, context.Background(), onlyEmpty)
+}
+
+func TestWithParamsNilContext(t *testing.T) {
+	ctx := WithParams(nil, map[string]string{"foo": "bar"})
+	require.NotNil(t, ctx)
+	assert.Equal(t, map[string]string{"foo": "bar"}, Params(ctx))
+
+	assert.Equal(t, context.Background(), WithParams(nil, nil))
+	assert.Equal(t, map[string]string{"foo": "bar"}, Params(WithParamsKV(nil, "foo", "bar")))
+	sev, ok := MinSeverity(WithMinSeverity(nil, DebugSeverity))
+	assert.True(t, ok)
+	assert.Equal(t, DebugSeverity, sev)
+}
+
+func TestParamsReadOnly(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	})
+	ctx = WithParams(ctx, map[string]string{
+		"foo": "overwritten",
+	})
+
+	assert.Equal(t, Params(ctx), ParamsReadOnly(ctx))
+	assert.Nil(t, ParamsReadOnly(context.Background()))
+
+	// Params returns a copy, so mutating it doesn't affect the context
+	Params(ctx)["foo"] = "mutated"
+	assert.Equal(t, "overwritten", ParamsReadOnly(ctx)["foo"])
+}
+
+func TestRangeParams(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	})
+	ctx = WithParams(ctx, map[string]string{
+		"foo": "overwritten",
+	})
+
+	ranged := map[string]string{}
+	RangeParams(ctx, func(k, v string) bool {
+		ranged[k] = v
+		return true
+	})
+	assert.Equal(t, Params(ctx), ranged)
+
+	calls := 0
+	RangeParams(ctx, func(k, v string) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+
+	RangeParams(context.Background(), func(k, v string) bool {
+		t.Errorf("unexpected param %s", k)
+		return true
+	})
+}
+
+func TestWithParamMaps(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"request_id": "r1",
+		"source":     "parent",
+	})
+	defaults := map[string]string{"source": "defaults", "region": "eu"}
+	request := map[string]string{"source": "request", "user_id": "u1"}
+	ctx = WithParamMaps(ctx, defaults, nil, request, map[string]string{"": "dropped"})
+
+	assert.Equal(t, map[string]string{
+		"request_id": "r1",
+		"source":     "request",
+		"region":     "eu",
+		"user_id":    "u1",
+	}, Params(ctx))
+
+	// The params are added as a single layer, and the maps aren't retained
+	layer := ctx.Value(paramsKey{DefaultParamsNamespace}).(*paramsLayer)
+	assert.Len(t, layer.params, 3)
+	request["user_id"] = "changed"
+	assert.Equal(t, "u1", Params(ctx)["user_id"])
+
+	assert.Equal(t, ctx, WithParamMaps(ctx))
+	assert.Equal(t, ctx, WithParamMaps(ctx, nil, map[string]string{}))
+	assert.Empty(t, Params(WithParamMaps(nil)))
+}
+
+func TestWithFreshParams(t *testing.T) {
+	parent := WithParams(context.Background(), map[string]string{
+		"request_id": "r1",
+		"user_id":    "u1",
+	})
+	parent = WithNamespacedParams(parent, "other", map[string]string{"kept": "true"})
+	ctx := WithFreshParams(parent, map[string]string{
+		"job_id": "j1",
+		"":       "dropped",
+	})
+
+	assert.Equal(t, map[string]string{"job_id": "j1"}, Params(ctx))
+	assert.Equal(t, map[string]interface{}{"job_id": "j1"}, Eventf(InfoSeverity, ctx, "Fresh").Metadata)
+	ranged := map[string]string{}
+	RangeParams(ctx, func(k, v string) bool {
+		ranged[k] = v
+		return true
+	})
+	assert.Equal(t, map[string]string{"job_id": "j1"}, ranged)
+	assert.Equal(t, map[string]string{"kept": "true"}, NamespacedParams(ctx, "other"))
+
+	// The parent is unaffected, and params added later merge with the fresh ones
+	assert.Equal(t, "r1", Params(parent)["request_id"])
+	ctx = WithParams(ctx, map[string]string{"step": "2"})
+	assert.Equal(t, map[string]string{"job_id": "j1", "step": "2"}, Params(ctx))
+
+	assert.Empty(t, Params(WithFreshParams(parent, nil)))
+	assert.Empty(t, Params(WithFreshParams(nil, nil)))
+}
+
+func TestWithParamsKV(t *testing.T) {
+	ctx := WithParamsKV(context.Background(), "foo", "bar", "baz", "qux")
+	assert.Equal(t, map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	}, Params(ctx))
+
+	assert.Panics(t, func() {
+		WithParamsKV(context.Background(), "foo")
+	})
+}
+
+func TestNamespacedParams(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"foo": "bar",
+	})
+	ctx = WithNamespacedParams(ctx, "internal", map[string]string{
+		"diagnostic": "value",
+	})
+	ctx = WithNamespacedParams(ctx, "internal", map[string]string{
+		"other": "value",
+	})
+
+	assert.Equal(t, map[string]string{
+		"foo": "bar",
+	}, Params(ctx))
+	assert.Equal(t, map[string]string{
+		"diagnostic": "value",
+		"other":      "value",
+	}, NamespacedParams(ctx, "internal"))
+
+	e := Eventf(InfoSeverity, ctx, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"foo": "bar",
+	}, e.Metadata)
+
+	SetMetadataParamsNamespaces(DefaultParamsNamespace, "internal")
+	defer SetMetadataParamsNamespaces(DefaultParamsNamespace)
+	e = Eventf(InfoSeverity, ctx, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"foo":        "bar",
+		"diagnostic": "value",
+		"other":      "value",
+	}, e.Metadata)
+}
+
+func TestTypedParams(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"count":  "1",
+		"string": "value",
+	})
+	ctx = WithTypedParams(ctx, map[string]interface{}{
+		"count":   1,
+		"enabled": true,
+	})
+	ctx = WithTypedParams(ctx, map[string]interface{}{
+		"enabled": false,
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"count":   1,
+		"enabled": false,
+	}, TypedParams(ctx))
+	assert.Nil(t, TypedParams(context.Background()))
+
+	e := Eventf(InfoSeverity, ctx, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"count":   1,
+		"enabled": false,
+		"string":  "value",
+	}, e.Metadata)
+}
+
+func TestCopyParams(t *testing.T) {
+	src, cancel := context.WithCancel(context.Background())
+	src = WithParams(src, map[string]string{
+		"request_id": "r1",
+		"shared":     "src",
+	})
+	src = WithTypedParams(src, map[string]interface{}{
+		"attempt": 2,
+	})
+	dst := WithParams(context.Background(), map[string]string{
+		"shared": "dst",
+		"worker": "w1",
+	})
+
+	copied := CopyParams(dst, src)
+	cancel()
+	assert.NoError(t, copied.Err())
+
+	e := Eventf(InfoSeverity, copied, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"request_id": "r1",
+		"shared":     "src",
+		"worker":     "w1",
+		"attempt":    2,
+	}, e.Metadata)
+}
+
+func TestWithParamsDoesNotRetainCallerMap(t *testing.T) {
+	params := map[string]string{
+		"foo": "bar",
+	}
+	ctx := WithParams(context.Background(), params)
+	params["foo"] = "changed"
+
+	assert.Equal(t, map[string]string{
+		"foo": "bar",
+	}, Params(ctx))
+}
+
+func TestWithParamsSiblingIsolation(t *testing.T) {
+	parent := WithParams(context.Background(), map[string]string{
+		"request_id": "r1",
+	})
+	// Read the parent first, so its merged params are cached before the siblings derive from it
+	require.Equal(t, "r1", Params(parent)["request_id"])
+
+	const siblings = 10
+	results := make([]map[string]string, siblings)
+	var wg sync.WaitGroup
+	for i := 0; i < siblings; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := parent
+			for j := 0; j < 100; j++ {
+				ctx = WithParams(ctx, map[string]string{
+					"worker":                  strconv.Itoa(i),
+					"step_" + strconv.Itoa(i): strconv.Itoa(j),
+				})
+				ParamsReadOnly(ctx)
+			}
+			results[i] = Params(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, params := range results {
+		assert.Equal(t, map[string]string{
+			"request_id":              "r1",
+			"worker":                  strconv.Itoa(i),
+			"step_" + strconv.Itoa(i): "99",
+		}, params)
+	}
+	assert.Equal(t, map[string]string{
+		"request_id": "r1",
+	}, Params(parent))
+}
+
+func Test_InlineParamsTakePrecedenceOverContextParams(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{
+		"foo":      "context",
+		"provider": "context",
+		"context":  "context",
+	})
+
+	e := Eventf(InfoSeverity, ctx, "foo", testLogMetadataProvider{
+		"provider": "provider",
+	}, map[string]interface{}{
+		"foo": "inline",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"foo":      "inline",
+		"provider": "provider",
+		"context":  "context",
+	}, e.Metadata)
+}
+
+func TestAnnotateContextState(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	SetClock(func() time.Time {
+		return deadline.Add(-time.Second)
+	})
+	defer ResetClock()
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := Eventf(InfoSeverity, ctx, "foo")
+	assert.Nil(t, e.Metadata)
+
+	SetAnnotateContextState(true)
+	defer SetAnnotateContextState(false)
+
+	e = Eventf(InfoSeverity, ctx, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"ctx_deadline_in_ms": int64(1000),
+	}, e.Metadata)
+
+	e = Eventf(InfoSeverity, cancelled, "foo")
+	assert.Equal(t, map[string]interface{}{
+		"ctx_error": "context canceled",
+	}, e.Metadata)
+
+	e = Eventf(InfoSeverity, nil, "foo")
+	assert.Nil(t, e.Metadata)
+}
+
+func TestParamsCompaction(t *testing.T) {
+	defer SetMaxParamsDepth(DefaultMaxParamsDepth)
+	build := func() context.Context {
+		ctx := context.Background()
+		for i := 0; i < 10; i++ {
+			ctx = WithParams(ctx, map[string]string{
+				"step":                  strconv.Itoa(i),
+				"key" + strconv.Itoa(i): "value",
+			})
+		}
+		return ctx
+	}
+
+	SetMaxParamsDepth(0)
+	uncompacted := build()
+	assert.Equal(t, 10, uncompacted.Value(paramsKey{DefaultParamsNamespace}).(*paramsLayer).depth)
+
+	SetMaxParamsDepth(4)
+	compacted := build()
+	layer := compacted.Value(paramsKey{DefaultParamsNamespace}).(*paramsLayer)
+	assert.Equal(t, 2, layer.depth)
+	// The ninth layer holds everything added up to it
+	assert.Len(t, layer.parent.params, 10)
+	assert.Nil(t, layer.parent.parent)
+
+	expected := Params(uncompacted)
+	assert.Len(t, expected, 11)
+	assert.Equal(t, "9", expected["step"])
+	assert.Equal(t, expected, Params(compacted))
+	ranged := map[string]string{}
+	RangeParams(compacted, func(k, v string) bool {
+		ranged[k] = v
+		return true
+	})
+	assert.Equal(t, expected, ranged)
+}
+
+func BenchmarkWithParamsKV(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		WithParamsKV(context.Background(), "a", "1", "b", "2", "c", "3")
+	}
+}
+
+func BenchmarkWithParamsChained(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ctx := WithParams(context.Background(), map[string]string{"a": "1"})
+		ctx = WithParams(ctx, map[string]string{"b": "2"})
+		WithParams(ctx, map[string]string{"c": "3"})
+	}
+}
+
+func BenchmarkParams(b *testing.B) {
+	ctx := WithParamsKV(context.Background(), "a", "1", "b", "2")
+	ctx = WithParamsKV(ctx, "c", "3")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Params(ctx)
+	}
+}
+
+func BenchmarkParamsReadOnly(b *testing.B) {
+	ctx := WithParamsKV(context.Background(), "a", "1", "b", "2")
+	ctx = WithParamsKV(ctx, "c", "3")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParamsReadOnly(ctx)
+	}
+}
+
+func BenchmarkRangeParams(b *testing.B) {
+	ctx := WithParamsKV(context.Background(), "a", "1", "b", "2")
+	ctx = WithParamsKV(ctx, "c", "3")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RangeParams(ctx, func(k, v string) bool {
+			return true
+		})
+	}
+}
+
+func benchmarkRangeParamsDeep(b *testing.B, maxDepth int) {
+	SetMaxParamsDepth(maxDepth)
+	defer SetMaxParamsDepth(DefaultMaxParamsDepth)
+	ctx := context.Background()
+	for i := 0; i < 500; i++ {
+		ctx = WithParamsKV(ctx, "step", strconv.Itoa(i), "key"+strconv.Itoa(i%50), "value")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RangeParams(ctx, func(k, v string) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkRangeParamsDeepUncompacted(b *testing.B) {
+	benchmarkRangeParamsDeep(b, 0)
+}
+
+func BenchmarkRangeParamsDeepCompacted(b *testing.B) {
+	benchmarkRangeParamsDeep(b, DefaultMaxParamsDepth)
+}
+
+func BenchmarkWithParamMaps(b *testing.B) {
+	defaults := map[string]string{"region": "eu", "source": "defaults"}
+	request := map[string]string{"request_id": "r1", "user_id": "u1", "source": "request"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WithParamMaps(context.Background(), defaults, request)
+	}
+}
+
+func BenchmarkWithParamsPremerged(b *testing.B) {
+	defaults := map[string]string{"region": "eu", "source": "defaults"}
+	request := map[string]string{"request_id": "r1", "user_id": "u1", "source": "request"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		merged := make(map[string]string, len(defaults)+len(request))
+		for k, v := range defaults {
+			merged[k] = v
+		}
+		for k, v := range request {
+			merged[k] = v
+		}
+		WithParams(context.Background(), merged)
+	}
+}
@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"context"
+)
+
+// A ContextLogger gives events logged without a meaningful context a baseline one, before forwarding them to another
+// Logger. This lets a background worker establish its logging params once, rather than for every event.
+type ContextLogger struct {
+	next Logger
+	base context.Context
+}
+
+// NewContextLogger creates a ContextLogger which substitutes base for the context of events whose context is nil or
+// context.Background(), adding the params of base to their metadata. Events with any other context are forwarded
+// unchanged, as their own context takes precedence.
+func NewContextLogger(next Logger, base context.Context) *ContextLogger {
+	return &ContextLogger{
+		next: next,
+		base: base,
+	}
+}
+
+// Log the events to the underlying logger, substituting the base context where needed.
+func (l *ContextLogger) Log(evs ...Event) {
+	metadata := metadataFromContext(l.base)
+	decorated := make([]Event, len(evs))
+	for i, e := range evs {
+		if e.Context == nil || e.Context == context.Background() {
+			e.Context = l.base
+			// Copy the event's metadata rather than writing to it, as it may be shared with other loggers
+			if len(metadata) > 0 {
+				e.Metadata = mergeMetadata(mergeMetadata(nil, e.Metadata), metadata)
+			}
+		}
+		decorated[i] = e
+	}
+	l.next.Log(decorated...)
+}
+
+// Flush the underlying logger.
+func (l *ContextLogger) Flush() error {
+	return l.next.Flush()
+}
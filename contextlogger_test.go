@@ -0,0 +1,33 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	base := WithParams(context.Background(), map[string]string{
+		"worker": "w1",
+		"meta":   "base",
+	})
+	logger := NewContextLogger(next, base)
+
+	request := WithParams(context.Background(), map[string]string{"request_id": "r1"})
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "background", map[string]interface{}{"meta": "inline"}),
+		Event{Message: "nil context"},
+		Eventf(InfoSeverity, request, "request"))
+
+	events := next.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, base, events[0].Context)
+	assert.Equal(t, map[string]interface{}{"worker": "w1", "meta": "inline"}, events[0].Metadata)
+	assert.Equal(t, base, events[1].Context)
+	assert.Equal(t, map[string]interface{}{"worker": "w1", "meta": "base"}, events[1].Metadata)
+	assert.Equal(t, request, events[2].Context)
+	assert.Equal(t, map[string]interface{}{"request_id": "r1"}, events[2].Metadata)
+}
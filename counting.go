@@ -0,0 +1,51 @@
+package slog
+
+import "sync/atomic"
+
+// A CountingLogger counts events by severity before forwarding them to another Logger. Unlike slogprometheus, it has
+// no dependencies, so is suitable for simple reporting of logging activity, such as from a health check.
+type CountingLogger struct {
+	next Logger
+	// counts is indexed by severity. Events with other severities are only counted in total.
+	counts [CriticalSeverity + 1]uint64
+	total  uint64
+}
+
+// NewCountingLogger creates a CountingLogger which forwards events to next.
+func NewCountingLogger(next Logger) *CountingLogger {
+	return &CountingLogger{
+		next: next,
+	}
+}
+
+// Log counts the events and forwards them to the underlying logger.
+func (l *CountingLogger) Log(evs ...Event) {
+	for _, e := range evs {
+		if e.Severity >= 0 && int(e.Severity) < len(l.counts) {
+			atomic.AddUint64(&l.counts[e.Severity], 1)
+		}
+	}
+	atomic.AddUint64(&l.total, uint64(len(evs)))
+	l.next.Log(evs...)
+}
+
+// Flush the underlying logger.
+func (l *CountingLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// Counts returns the number of events logged with each severity which has been logged.
+func (l *CountingLogger) Counts() map[Severity]uint64 {
+	counts := map[Severity]uint64{}
+	for sev := range l.counts {
+		if n := atomic.LoadUint64(&l.counts[sev]); n > 0 {
+			counts[Severity(sev)] = n
+		}
+	}
+	return counts
+}
+
+// Total returns the number of events logged.
+func (l *CountingLogger) Total() uint64 {
+	return atomic.LoadUint64(&l.total)
+}
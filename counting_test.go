@@ -0,0 +1,38 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewCountingLogger(next)
+	assert.Empty(t, logger.Counts())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Log(
+				Eventf(InfoSeverity, context.Background(), "info"),
+				Eventf(ErrorSeverity, context.Background(), "error"),
+				Eventf(InfoSeverity, context.Background(), "info"))
+		}()
+	}
+	wg.Wait()
+	logger.Log(Eventf(CriticalSeverity, context.Background(), "critical"), Event{Severity: Severity(42)})
+
+	assert.Equal(t, map[Severity]uint64{
+		InfoSeverity:     20,
+		ErrorSeverity:    10,
+		CriticalSeverity: 1,
+	}, logger.Counts())
+	assert.EqualValues(t, 32, logger.Total())
+	assert.Len(t, next.Events(), 32)
+	assert.NoError(t, logger.Flush())
+}
@@ -0,0 +1,83 @@
+package slog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// A CSVLogger writes events as rows of CSV, quoted as described by RFC 4180, for analysis in a spreadsheet. The first
+// row written is a header of the column names.
+type CSVLogger struct {
+	writeErrors
+	m             sync.Mutex
+	w             *csv.Writer
+	columns       []string
+	headerWritten bool
+}
+
+// NewCSVLogger creates a CSVLogger which writes the given columns of each event to w. Columns are looked up by the
+// keys of Event.Fields: the well-known keys "id", "timestamp", "severity", "message" and "error", metadata keys, and
+// labels as "label.<key>". Columns missing from an event are left empty.
+func NewCSVLogger(w io.Writer, columns []string) *CSVLogger {
+	return &CSVLogger{
+		w:       csv.NewWriter(w),
+		columns: append([]string(nil), columns...),
+	}
+}
+
+// Log writes a row for each event, preceded by the header if it hasn't been written yet. Rows are buffered until the
+// buffer fills or Flush is called, and any write error is reported by the next call to Flush.
+func (l *CSVLogger) Log(evs ...Event) {
+	if len(evs) == 0 {
+		return
+	}
+	l.m.Lock()
+	defer l.m.Unlock()
+	if !l.headerWritten {
+		l.record(l.w.Write(l.columns))
+		l.headerWritten = true
+	}
+
+	row := make([]string, len(l.columns))
+	for _, e := range evs {
+		fields := e.Fields()
+		for i, column := range l.columns {
+			row[i] = ""
+			if v, ok := fields[column]; ok {
+				row[i] = csvValue(v)
+			}
+		}
+		l.record(l.w.Write(row))
+	}
+}
+
+// Flush writes any buffered rows to the underlying writer, and returns the first error encountered since the last
+// Flush.
+func (l *CSVLogger) Flush() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.w.Flush()
+	l.record(l.w.Error())
+	return l.takeError()
+}
+
+// csvValue renders a field of an event as a CSV cell.
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return formatError(v)
+	case []byte:
+		return encodeBytes(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
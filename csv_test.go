@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewCSVLogger(buf, []string{"timestamp", "severity", "message", "error", "user_id", "label.team", "missing"})
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	logger.Log(
+		EventfAt(ts, InfoSeverity, context.Background(), "Loaded %s", "widget", map[string]interface{}{
+			"user_id": 42,
+		}).WithLabel("team", "payments"),
+		EventfAt(ts, ErrorSeverity, context.Background(), `Failed, with "quotes"`+"\nand a newline", errors.New("boom")),
+	)
+	assert.Empty(t, buf.String(), "rows should be buffered until Flush")
+	require.NoError(t, logger.Flush())
+
+	assert.Equal(t, "timestamp,severity,message,error,user_id,label.team,missing\n"+
+		"2020-01-02T03:04:05.0000006Z,INFO,Loaded widget,,42,payments,\n"+
+		"2020-01-02T03:04:05.0000006Z,ERROR,\"Failed, with \"\"quotes\"\"\nand a newline\",boom,,,\n", buf.String())
+
+	// The output round-trips, and the header is only written once
+	logger.Log(Eventf(WarnSeverity, context.Background(), "Again"))
+	require.NoError(t, logger.Flush())
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, `Failed, with "quotes"`+"\nand a newline", records[2][2])
+	assert.Equal(t, []string{"WARN", "Again"}, records[3][1:3])
+}
+
+func TestCSVLoggerWriteError(t *testing.T) {
+	diskFull := errors.New("disk full")
+	logger := NewCSVLogger(&failingWriter{err: diskFull}, []string{"message"})
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	assert.Equal(t, diskFull, logger.Flush())
+	assert.Equal(t, diskFull, logger.LastError())
+}
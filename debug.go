@@ -0,0 +1,44 @@
+//go:build !slogprod
+// +build !slogprod
+
+package slog
+
+import "context"
+
+// Debug constructs a logging event with debug severity. If the
+// default Logger implements the LeveledLogger interface, we forward the
+// requests via the Debug interface function. If not, the event is sent
+// via the default Logger
+func Debug(ctx context.Context, msg string, params ...interface{}) {
+	if l := loggerFor(ctx); l != nil {
+		if ll, ok := l.(LeveledLogger); ok {
+			logLeveled(l, ll.Debug, DebugSeverity, ctx, msg, params...)
+		} else {
+			logAndNotify(l, Eventf(DebugSeverity, ctx, msg, params...))
+		}
+	}
+}
+
+// Trace constructs a logging event with trace severity. If the
+// default Logger implements the LeveledLogger interface, we forward the
+// requests via the Trace interface function. If not, the event is sent
+// via the default Logger
+func Trace(ctx context.Context, msg string, params ...interface{}) {
+	if l := loggerFor(ctx); l != nil {
+		if ll, ok := l.(LeveledLogger); ok {
+			logLeveled(l, ll.Trace, TraceSeverity, ctx, msg, params...)
+		} else {
+			logAndNotify(l, Eventf(TraceSeverity, ctx, msg, params...))
+		}
+	}
+}
+
+// Debugt logs a templated event with debug severity via the default Logger (or one carried by the context). See Eventft.
+func Debugt(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(DebugSeverity, ctx, template, metadata))
+}
+
+// Tracet logs a templated event with trace severity via the default Logger (or one carried by the context). See Eventft.
+func Tracet(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(TraceSeverity, ctx, template, metadata))
+}
@@ -0,0 +1,24 @@
+//go:build slogprod
+// +build slogprod
+
+package slog
+
+import "context"
+
+// Debug does nothing: building with the slogprod tag compiles debug logging out entirely, both so that it costs
+// nothing and so that it can't leak internals in production binaries. As the function is empty, calls to it are
+// inlined away, but Go still evaluates the arguments of the call unless the compiler can prove that doing so has no
+// side effects, so arguments which are expensive to build still cost something.
+//
+// The tradeoff is that debug logging can't be turned on in a slogprod binary, even temporarily to diagnose a problem.
+// Events built with Eventf and logged directly are unaffected.
+func Debug(ctx context.Context, msg string, params ...interface{}) {}
+
+// Trace does nothing: building with the slogprod tag compiles trace logging out entirely. See Debug.
+func Trace(ctx context.Context, msg string, params ...interface{}) {}
+
+// Debugt does nothing: building with the slogprod tag compiles debug logging out entirely. See Debug.
+func Debugt(ctx context.Context, template string, metadata map[string]interface{}) {}
+
+// Tracet does nothing: building with the slogprod tag compiles trace logging out entirely. See Debug.
+func Tracet(ctx context.Context, template string, metadata map[string]interface{}) {}
@@ -0,0 +1,40 @@
+//go:build slogprod
+// +build slogprod
+
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugCompiledOut(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Trace(context.Background(), "Important trace message", "foo")
+	Debug(context.Background(), "Important debug message", "foo")
+	Tracet(context.Background(), "Important {kind} message", map[string]interface{}{"kind": "trace"})
+	Debugt(context.Background(), "Important {kind} message", map[string]interface{}{"kind": "debug"})
+	Info(context.Background(), "Important info message", "foo")
+
+	events := logger.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, InfoSeverity, events[0].Severity)
+}
+
+func TestDebugCompiledOutWithLeveledLogger(t *testing.T) {
+	logger := &testLogLeveledLogger{t: t}
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Trace(context.Background(), "Important trace message", "foo")
+	Debug(context.Background(), "Important debug message", "foo")
+	assert.Empty(t, logger.items)
+}
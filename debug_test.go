@@ -0,0 +1,69 @@
+//go:build !slogprod
+// +build !slogprod
+
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Trace(context.Background(), "Important trace message", "foo")
+	Debug(context.Background(), "Important debug message", "foo")
+	Info(context.Background(), "Important info message", "foo")
+	Warn(context.Background(), "Important warn message", "foo")
+	Error(context.Background(), "Important error message", "foo")
+	Critical(context.Background(), "Important critical message", "foo")
+
+	events := logger.Events()
+	require.Equal(t, 6, len(events))
+	assert.Equal(t, TraceSeverity, events[0].Severity)
+	assert.Equal(t, DebugSeverity, events[1].Severity)
+	assert.Equal(t, InfoSeverity, events[2].Severity)
+	assert.Equal(t, WarnSeverity, events[3].Severity)
+	assert.Equal(t, ErrorSeverity, events[4].Severity)
+	assert.Equal(t, CriticalSeverity, events[5].Severity)
+}
+
+func TestDefaultLoggerWithLeveledLogger(t *testing.T) {
+	logger := &testLogLeveledLogger{t: t}
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Trace(context.Background(), "Important trace message", "foo")
+	Debug(context.Background(), "Important debug message", "foo")
+	Info(context.Background(), "Important info message", "foo")
+	Warn(context.Background(), "Important warn message", "foo")
+	Error(context.Background(), "Important error message", "foo")
+	Critical(context.Background(), "Important critical message", "foo")
+
+	require.Equal(t, 6, len(logger.items))
+
+	assert.Equal(t, TraceSeverity, logger.items[0].Severity)
+	assert.Equal(t, "Important trace message", logger.items[0].OriginalMessage)
+
+	assert.Equal(t, DebugSeverity, logger.items[1].Severity)
+	assert.Equal(t, "Important debug message", logger.items[1].OriginalMessage)
+
+	assert.Equal(t, InfoSeverity, logger.items[2].Severity)
+	assert.Equal(t, "Important info message", logger.items[2].OriginalMessage)
+
+	assert.Equal(t, WarnSeverity, logger.items[3].Severity)
+	assert.Equal(t, "Important warn message", logger.items[3].OriginalMessage)
+
+	assert.Equal(t, ErrorSeverity, logger.items[4].Severity)
+	assert.Equal(t, "Important error message", logger.items[4].OriginalMessage)
+
+	assert.Equal(t, CriticalSeverity, logger.items[5].Severity)
+	assert.Equal(t, "Important critical message", logger.items[5].OriginalMessage)
+}
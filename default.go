@@ -2,6 +2,7 @@ package slog
 
 import (
 	"context"
+	"errors"
 	"sync"
 )
 
@@ -22,10 +23,99 @@ func SetDefaultLogger(l Logger) {
 	defaultLogger = l
 }
 
+// Enabled reports whether events of the given severity logged with the context would be logged by the default
+// Logger, or the Logger carried by the context (see WithLogger). It can be used to skip building expensive metadata:
+//
+//	if slog.Enabled(ctx, slog.DebugSeverity) {
+//		slog.Debug(ctx, "State", expensiveMetadata())
+//	}
+//
+// If the Logger doesn't implement LevelEnabler, Enabled returns true. It errs on the side of returning true,
+// so an event may still be dropped after it is built, but it is never false for an event which would be logged.
+func Enabled(ctx context.Context, sev Severity) bool {
+	return loggerEnabled(loggerFor(ctx), ctx, sev)
+}
+
+// loggerEnabled reports whether events of the given severity logged with the context would be logged by l, as for
+// Enabled.
+func loggerEnabled(l Logger, ctx context.Context, sev Severity) bool {
+	if l == nil {
+		return false
+	}
+	if enabled(l, sev) {
+		return true
+	}
+	// A context override may lower the threshold of a LevelFilterLogger below what it reports for itself
+	if min, ok := MinSeverity(ctx); ok && sev >= min {
+		return true
+	}
+	return false
+}
+
+// ErrMinSeverityUnsupported is returned by SetDefaultMinSeverity if the default Logger's minimum severity can't be
+// changed.
+var ErrMinSeverityUnsupported = errors.New("slog: default logger does not support setting a minimum severity")
+
+// DefaultMinSeverity returns the lowest severity of events which the default Logger would log, according to its
+// LevelEnabler implementation. If it doesn't implement LevelEnabler, it is assumed to log everything and
+// TraceSeverity is returned. If it logs nothing, a severity above CriticalSeverity is returned.
+func DefaultMinSeverity() Severity {
+	l := DefaultLogger()
+	if l == nil {
+		return CriticalSeverity + 1
+	}
+	for sev := TraceSeverity; sev <= CriticalSeverity; sev++ {
+		if enabled(l, sev) {
+			return sev
+		}
+	}
+	return CriticalSeverity + 1
+}
+
+// SetDefaultMinSeverity changes the minimum severity of the default Logger, if it implements MinSeveritySetter (as
+// LevelFilterLogger does). Otherwise, it returns ErrMinSeverityUnsupported.
+func SetDefaultMinSeverity(sev Severity) error {
+	l, ok := DefaultLogger().(MinSeveritySetter)
+	if !ok {
+		return ErrMinSeverityUnsupported
+	}
+	l.SetMinSeverity(sev)
+	return nil
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of the parent context which carries the given Logger. The package-level logging functions
+// which take a context (Info, Error, FromError and so on) send events logged with it to that Logger, in preference to
+// the default Logger. This allows a block of code to log to a particular Logger without changing global state.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// loggerFor returns the Logger carried by the context, if any, or otherwise the default Logger.
+func loggerFor(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+			return l
+		}
+	}
+	return DefaultLogger()
+}
+
 // Log sends the given Events via the default Logger
 func Log(evs ...Event) {
 	if l := DefaultLogger(); l != nil {
-		l.Log(evs...)
+		logAndNotify(l, evs...)
+	}
+}
+
+// logTo sends the given Events via the Logger carried by the context, or the default Logger.
+func logTo(ctx context.Context, evs ...Event) {
+	if l := loggerFor(ctx); l != nil {
+		logAndNotify(l, evs...)
 	}
 }
 
@@ -34,11 +124,11 @@ func Log(evs ...Event) {
 // requests via the Critical interface function. If not, the event is sent
 // via the default Logger
 func Critical(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
+	if l := loggerFor(ctx); l != nil {
 		if ll, ok := l.(LeveledLogger); ok {
-			ll.Critical(ctx, msg, params...)
+			logLeveled(l, ll.Critical, CriticalSeverity, ctx, msg, params...)
 		} else {
-			l.Log(Eventf(CriticalSeverity, ctx, msg, params...))
+			logAndNotify(l, Eventf(CriticalSeverity, ctx, msg, params...))
 		}
 	}
 }
@@ -48,11 +138,11 @@ func Critical(ctx context.Context, msg string, params ...interface{}) {
 // requests via the Error interface function. If not, the event is sent
 // via the default Logger
 func Error(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
+	if l := loggerFor(ctx); l != nil {
 		if ll, ok := l.(LeveledLogger); ok {
-			ll.Error(ctx, msg, params...)
+			logLeveled(l, ll.Error, ErrorSeverity, ctx, msg, params...)
 		} else {
-			l.Log(Eventf(ErrorSeverity, ctx, msg, params...))
+			logAndNotify(l, Eventf(ErrorSeverity, ctx, msg, params...))
 		}
 	}
 }
@@ -62,11 +152,11 @@ func Error(ctx context.Context, msg string, params ...interface{}) {
 // requests via the Warn interface function. If not, the event is sent
 // via the default Logger
 func Warn(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
+	if l := loggerFor(ctx); l != nil {
 		if ll, ok := l.(LeveledLogger); ok {
-			ll.Warn(ctx, msg, params...)
+			logLeveled(l, ll.Warn, WarnSeverity, ctx, msg, params...)
 		} else {
-			l.Log(Eventf(WarnSeverity, ctx, msg, params...))
+			logAndNotify(l, Eventf(WarnSeverity, ctx, msg, params...))
 		}
 	}
 }
@@ -76,54 +166,56 @@ func Warn(ctx context.Context, msg string, params ...interface{}) {
 // requests via the Info interface function. If not, the event is sent
 // via the default Logger
 func Info(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
+	if l := loggerFor(ctx); l != nil {
 		if ll, ok := l.(LeveledLogger); ok {
-			ll.Info(ctx, msg, params...)
+			logLeveled(l, ll.Info, InfoSeverity, ctx, msg, params...)
 		} else {
-			l.Log(Eventf(InfoSeverity, ctx, msg, params...))
+			logAndNotify(l, Eventf(InfoSeverity, ctx, msg, params...))
 		}
 	}
 }
 
-// Debug constructs a logging event with debug severity. If the
-// default Logger implements the LeveledLogger interface, we forward the
-// requests via the Debug interface function. If not, the event is sent
-// via the default Logger
-func Debug(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
-		if ll, ok := l.(LeveledLogger); ok {
-			ll.Debug(ctx, msg, params...)
-		} else {
-			l.Log(Eventf(DebugSeverity, ctx, msg, params...))
+// FromError constructs a logging event whose severity is inferred from
+// the error. If the default Logger implements the FromErrorLogger
+// interface, we forward the requests via the FromError interface
+// function, which is responsible for inferring the severity. If not, the
+// severity is given by SeverityForError, and the event is forwarded via
+// the corresponding LeveledLogger function if the default Logger
+// implements that interface, or sent via the default Logger otherwise.
+// In both cases, the error is the first param of the event.
+func FromError(ctx context.Context, msg string, err error, params ...interface{}) {
+	if l := loggerFor(ctx); l != nil {
+		if ll, ok := l.(FromErrorLogger); ok {
+			notifyLeveled(l, func(ctx context.Context) {
+				ll.FromError(ctx, msg, err, params...)
+			}, SeverityForError(err), ctx, msg, append([]interface{}{err}, params...)...)
+			return
 		}
-	}
-}
 
-// Trace constructs a logging event with trace severity. If the
-// default Logger implements the LeveledLogger interface, we forward the
-// requests via the Trace interface function. If not, the event is sent
-// via the default Logger
-func Trace(ctx context.Context, msg string, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
+		sev := SeverityForError(err)
+		params = append([]interface{}{err}, params...)
 		if ll, ok := l.(LeveledLogger); ok {
-			ll.Trace(ctx, msg, params...)
+			logLeveled(l, leveledFunc(ll, sev), sev, ctx, msg, params...)
 		} else {
-			l.Log(Eventf(TraceSeverity, ctx, msg, params...))
+			logAndNotify(l, Eventf(sev, ctx, msg, params...))
 		}
 	}
 }
 
-// FromError constructs a logging event with error severity by default.
-// If the default Logger implements the FromErrorLogger interface, we
-// forward the requests via the FromError interface function. In this
-// case the severity will be inferred from the error.
-func FromError(ctx context.Context, msg string, err error, params ...interface{}) {
-	if l := DefaultLogger(); l != nil {
-		if ll, ok := l.(FromErrorLogger); ok {
-			ll.FromError(ctx, msg, err, params...)
-		} else {
-			params = append([]interface{}{err}, params...)
-			l.Log(Eventf(ErrorSeverity, ctx, msg, params...))
-		}
+// leveledFunc returns the function of ll which logs at the given severity.
+func leveledFunc(ll LeveledLogger, sev Severity) func(ctx context.Context, msg string, params ...interface{}) {
+	switch sev {
+	case CriticalSeverity:
+		return ll.Critical
+	case WarnSeverity:
+		return ll.Warn
+	case InfoSeverity:
+		return ll.Info
+	case DebugSeverity:
+		return ll.Debug
+	case TraceSeverity:
+		return ll.Trace
+	default:
+		return ll.Error
 	}
 }
@@ -9,79 +9,57 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestDefaultLogger(t *testing.T) {
-	logger := NewInMemoryLogger()
+func TestDefaultLoggerWithFromErrorLogger(t *testing.T) {
+	logger := &testLogFromErrorLogger{t: t}
 	oldLogger := DefaultLogger()
 	SetDefaultLogger(logger)
 	defer SetDefaultLogger(oldLogger)
 
-	Trace(context.Background(), "Important trace message", "foo")
-	Debug(context.Background(), "Important debug message", "foo")
-	Info(context.Background(), "Important info message", "foo")
-	Warn(context.Background(), "Important warn message", "foo")
-	Error(context.Background(), "Important error message", "foo")
-	Critical(context.Background(), "Important critical message", "foo")
+	FromError(context.Background(), "This error ends up as debug", context.Canceled, "foo")
+	FromError(context.Background(), "This error ends up as error", context.DeadlineExceeded, "foo")
 
-	events := logger.Events()
-	require.Equal(t, 6, len(events))
-	assert.Equal(t, TraceSeverity, events[0].Severity)
-	assert.Equal(t, DebugSeverity, events[1].Severity)
-	assert.Equal(t, InfoSeverity, events[2].Severity)
-	assert.Equal(t, WarnSeverity, events[3].Severity)
-	assert.Equal(t, ErrorSeverity, events[4].Severity)
-	assert.Equal(t, CriticalSeverity, events[5].Severity)
+	require.Equal(t, 2, len(logger.items))
+
+	assert.Equal(t, DebugSeverity, logger.items[0].Severity)
+	assert.Equal(t, "This error ends up as debug", logger.items[0].OriginalMessage)
+
+	assert.Equal(t, ErrorSeverity, logger.items[1].Severity)
+	assert.Equal(t, "This error ends up as error", logger.items[1].OriginalMessage)
 }
 
-func TestDefaultLoggerWithLeveledLogger(t *testing.T) {
-	logger := &testLogLeveledLogger{t: t}
+func TestFromErrorWithPlainLogger(t *testing.T) {
+	logger := NewInMemoryLogger()
 	oldLogger := DefaultLogger()
 	SetDefaultLogger(logger)
 	defer SetDefaultLogger(oldLogger)
 
-	Trace(context.Background(), "Important trace message", "foo")
-	Debug(context.Background(), "Important debug message", "foo")
-	Info(context.Background(), "Important info message", "foo")
-	Warn(context.Background(), "Important warn message", "foo")
-	Error(context.Background(), "Important error message", "foo")
-	Critical(context.Background(), "Important critical message", "foo")
-
-	require.Equal(t, 6, len(logger.items))
-
-	assert.Equal(t, TraceSeverity, logger.items[0].Severity)
-	assert.Equal(t, "Important trace message", logger.items[0].OriginalMessage)
-
-	assert.Equal(t, DebugSeverity, logger.items[1].Severity)
-	assert.Equal(t, "Important debug message", logger.items[1].OriginalMessage)
+	FromError(context.Background(), "Request cancelled", context.Canceled, "foo")
+	FromError(context.Background(), "Widget missing", &terrorsError{Code: "not_found.widget"})
+	FromError(context.Background(), "Failed to load widget", errors.New("boom"))
 
-	assert.Equal(t, InfoSeverity, logger.items[2].Severity)
-	assert.Equal(t, "Important info message", logger.items[2].OriginalMessage)
-
-	assert.Equal(t, WarnSeverity, logger.items[3].Severity)
-	assert.Equal(t, "Important warn message", logger.items[3].OriginalMessage)
-
-	assert.Equal(t, ErrorSeverity, logger.items[4].Severity)
-	assert.Equal(t, "Important error message", logger.items[4].OriginalMessage)
-
-	assert.Equal(t, CriticalSeverity, logger.items[5].Severity)
-	assert.Equal(t, "Important critical message", logger.items[5].OriginalMessage)
+	events := logger.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, WarnSeverity, events[0].Severity)
+	assert.Equal(t, context.Canceled, events[0].Error)
+	assert.Equal(t, WarnSeverity, events[1].Severity)
+	assert.Equal(t, "not_found.widget", events[1].Labels[ErrorCodeLabelKey])
+	assert.Equal(t, ErrorSeverity, events[2].Severity)
+	assert.EqualError(t, events[2].Error.(error), "boom")
 }
 
-func TestDefaultLoggerWithFromErrorLogger(t *testing.T) {
-	logger := &testLogFromErrorLogger{t: t}
+func TestFromErrorWithLeveledLogger(t *testing.T) {
+	logger := &testLogLeveledLogger{t: t}
 	oldLogger := DefaultLogger()
 	SetDefaultLogger(logger)
 	defer SetDefaultLogger(oldLogger)
 
-	FromError(context.Background(), "This error ends up as debug", context.Canceled, "foo")
-	FromError(context.Background(), "This error ends up as error", context.DeadlineExceeded, "foo")
+	FromError(context.Background(), "Request cancelled", context.Canceled)
+	FromError(context.Background(), "Failed to load widget", errors.New("boom"))
 
-	require.Equal(t, 2, len(logger.items))
-
-	assert.Equal(t, DebugSeverity, logger.items[0].Severity)
-	assert.Equal(t, "This error ends up as debug", logger.items[0].OriginalMessage)
-
-	assert.Equal(t, ErrorSeverity, logger.items[1].Severity)
-	assert.Equal(t, "This error ends up as error", logger.items[1].OriginalMessage)
+	assert.Equal(t, []logItem{
+		{Severity: WarnSeverity, OriginalMessage: "Request cancelled"},
+		{Severity: ErrorSeverity, OriginalMessage: "Failed to load widget"},
+	}, logger.items)
 }
 
 func TestNilDefaultLogger(t *testing.T) {
@@ -164,3 +142,57 @@ func (l *testLogFromErrorLogger) Log(evs ...Event) {
 func (l *testLogFromErrorLogger) Flush() error {
 	return nil
 }
+
+func TestEnabled(t *testing.T) {
+	oldLogger := DefaultLogger()
+	defer SetDefaultLogger(oldLogger)
+
+	SetDefaultLogger(NewInMemoryLogger())
+	assert.True(t, Enabled(context.Background(), TraceSeverity))
+
+	SetDefaultLogger(NewLevelFilterLogger(NewInMemoryLogger(), InfoSeverity))
+	assert.False(t, Enabled(context.Background(), DebugSeverity))
+	assert.True(t, Enabled(context.Background(), InfoSeverity))
+
+	verbose := WithMinSeverity(context.Background(), TraceSeverity)
+	assert.True(t, Enabled(verbose, DebugSeverity))
+}
+
+func TestDefaultMinSeverity(t *testing.T) {
+	oldLogger := DefaultLogger()
+	defer SetDefaultLogger(oldLogger)
+
+	SetDefaultLogger(NewInMemoryLogger())
+	assert.Equal(t, TraceSeverity, DefaultMinSeverity())
+	assert.Equal(t, ErrMinSeverityUnsupported, SetDefaultMinSeverity(ErrorSeverity))
+
+	logger := NewLevelFilterLogger(NewInMemoryLogger(), InfoSeverity)
+	SetDefaultLogger(logger)
+	assert.Equal(t, InfoSeverity, DefaultMinSeverity())
+	require.NoError(t, SetDefaultMinSeverity(ErrorSeverity))
+	assert.Equal(t, ErrorSeverity, DefaultMinSeverity())
+	assert.False(t, Enabled(context.Background(), WarnSeverity))
+}
+
+func TestWithLogger(t *testing.T) {
+	defaultLogger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(defaultLogger)
+	defer SetDefaultLogger(oldLogger)
+
+	scoped := NewInMemoryLogger()
+	ctx := WithLogger(context.Background(), scoped)
+
+	Info(ctx, "scoped")
+	FromError(ctx, "scoped error", errors.New("boom"))
+	Infot(ctx, "scoped {n}", map[string]interface{}{"n": 1})
+	Info(context.Background(), "default")
+	Info(nil, "nil context")
+
+	assert.Equal(t, []string{"scoped", "scoped error", "scoped 1"}, messages(scoped.Events()))
+	assert.Equal(t, []string{"default", "nil context"}, messages(defaultLogger.Events()))
+
+	// A nil Logger in the context falls back to the default
+	Info(WithLogger(context.Background(), nil), "fallback")
+	assert.Equal(t, 3, defaultLogger.Len())
+}
@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EqualIgnoring reports whether the events are equal, disregarding the named fields (e.g. "Id" and "Timestamp").
+// Ignoring Timestamp also ignores Sequence, which likewise reflects only when the event was built.
+func (e Event) EqualIgnoring(other Event, fields ...string) bool {
+	return len(diffEvents(e, other, fields)) == 0
+}
+
+// DiffEvents returns a readable description of the fields which differ between the events, one per line, or an empty
+// string if they are equal.
+func DiffEvents(a, b Event) string {
+	return strings.Join(diffEvents(a, b, nil), "\n")
+}
+
+func diffEvents(a, b Event, ignore []string) []string {
+	ignored := make(map[string]struct{}, len(ignore))
+	for _, f := range ignore {
+		ignored[f] = struct{}{}
+		if f == "Timestamp" {
+			ignored["Sequence"] = struct{}{}
+		}
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := av.Type()
+	diffs := []string(nil)
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if _, ok := ignored[name]; ok {
+			continue
+		}
+		x, y := av.Field(i).Interface(), bv.Field(i).Interface()
+		if !reflect.DeepEqual(x, y) {
+			diffs = append(diffs, fmt.Sprintf("%s: %#v != %#v", name, x, y))
+		}
+	}
+	return diffs
+}
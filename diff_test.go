@@ -0,0 +1,28 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEqualIgnoring(t *testing.T) {
+	ctx := context.Background()
+	a := Eventf(InfoSeverity, ctx, "foo", map[string]interface{}{"bar": 1})
+	b := Eventf(InfoSeverity, ctx, "foo", map[string]interface{}{"bar": 1})
+
+	assert.False(t, a.EqualIgnoring(b))
+	assert.True(t, a.EqualIgnoring(b, "Id", "Timestamp"))
+
+	b.Severity = ErrorSeverity
+	assert.False(t, a.EqualIgnoring(b, "Id", "Timestamp"))
+}
+
+func TestDiffEvents(t *testing.T) {
+	a := Event{Message: "foo", Severity: InfoSeverity}
+	assert.Equal(t, "", DiffEvents(a, a))
+
+	b := Event{Message: "bar", Severity: InfoSeverity}
+	assert.Equal(t, `Message: "foo" != "bar"`, DiffEvents(a, b))
+}
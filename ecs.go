@@ -0,0 +1,86 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ECSVersion is the version of the Elastic Common Schema written by ECSLogger.
+const ECSVersion = "1.6.0"
+
+// An ECSLogger writes each event as a line of JSON to an io.Writer, using Elastic Common Schema field names so that it
+// can be ingested without transformation. Labels are written to the ECS labels field, and metadata, which has no ECS
+// equivalent, is nested under "metadata".
+type ECSLogger struct {
+	writeErrors
+	m sync.Mutex
+	w io.Writer
+}
+
+// NewECSLogger creates an ECSLogger which writes to w.
+func NewECSLogger(w io.Writer) *ECSLogger {
+	return &ECSLogger{
+		w: w,
+	}
+}
+
+// Log writes the events to the underlying writer. Any error is reported by the next call to Flush.
+func (l *ECSLogger) Log(evs ...Event) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, e := range evs {
+		fields := ecsFields(e)
+		b, err := json.Marshal(fields)
+		if err != nil && len(e.Metadata) > 0 {
+			// As with Event.MarshalJSON, don't lose the event because of unserializable metadata
+			fields["metadata"] = serializableMetadata(e.Metadata)
+			b, err = json.Marshal(fields)
+		}
+		if err == nil {
+			_, err = l.w.Write(append(b, '\n'))
+		}
+		l.record(err)
+	}
+}
+
+// Flush the underlying writer if it supports flushing, and return the first error encountered since the last Flush.
+func (l *ECSLogger) Flush() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if f, ok := l.w.(interface{ Flush() error }); ok {
+		l.record(f.Flush())
+	}
+	return l.takeError()
+}
+
+// ecsFields returns the ECS representation of the event.
+func ecsFields(e Event) map[string]interface{} {
+	fields := map[string]interface{}{
+		"@timestamp":  e.Timestamp.Format(time.RFC3339Nano),
+		"log.level":   strings.ToLower(e.Severity.String()),
+		"message":     e.Message,
+		"event.id":    e.Id,
+		"ecs.version": ECSVersion,
+	}
+	if e.Sequence != 0 {
+		fields["event.sequence"] = e.Sequence
+	}
+	if len(e.Labels) > 0 {
+		fields["labels"] = e.Labels
+	}
+	if len(e.Metadata) > 0 {
+		fields["metadata"] = e.Metadata
+	}
+	if e.Error != nil {
+		if err, ok := e.Error.(error); ok {
+			fields["error.message"] = formatError(err)
+		} else {
+			fields["error.message"] = fmt.Sprint(e.Error)
+		}
+	}
+	return fields
+}
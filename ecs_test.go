@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECSLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewECSLogger(buf)
+
+	logger.Log(Event{
+		Id:        "id1",
+		Timestamp: time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC),
+		Severity:  ErrorSeverity,
+		Message:   "Failed to load widget",
+		Metadata:  map[string]interface{}{"widget_id": "w1"},
+		Labels:    map[string]string{"service": "widgets"},
+		Error:     errors.New("boom"),
+	}, Event{
+		Id:        "id2",
+		Timestamp: time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC),
+		Severity:  InfoSeverity,
+		Message:   "Loaded widget",
+	})
+	assert.NoError(t, logger.Flush())
+
+	expected := `{"@timestamp":"2020-01-02T03:04:05.6Z","ecs.version":"1.6.0","error.message":"boom","event.id":"id1",` +
+		`"labels":{"service":"widgets"},"log.level":"error","message":"Failed to load widget",` +
+		`"metadata":{"widget_id":"w1"}}` + "\n" +
+		`{"@timestamp":"2020-01-02T03:04:06Z","ecs.version":"1.6.0","event.id":"id2","log.level":"info",` +
+		`"message":"Loaded widget"}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestECSLoggerUnserializableMetadata(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewECSLogger(buf)
+
+	logger.Log(Event{
+		Id:        "id1",
+		Timestamp: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Severity:  InfoSeverity,
+		Message:   "Loaded widget",
+		Metadata:  map[string]interface{}{"ch": make(chan int)},
+	})
+	assert.NoError(t, logger.Flush())
+	assert.Contains(t, buf.String(), `"metadata":{"ch":"\u003cunserializable: chan int\u003e"}`)
+}
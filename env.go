@@ -0,0 +1,31 @@
+package slog
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultEnvLabelVariables are the environment variables whose values DefaultEnvLabels returns.
+var DefaultEnvLabelVariables = []string{"ENV", "DEPLOY_ENV", "REGION"}
+
+// EnvLabelsFromEnviron returns labels holding the values of the given environment variables, keyed by their names in
+// lower case (so ENV becomes env). Variables which are unset or empty are omitted. The result is suitable for
+// NewFieldsLogger, so that every event is labelled with, for example, the deployment environment:
+//
+//	logger := slog.NewFieldsLogger(next, slog.EnvLabelsFromEnviron("DEPLOY_ENV"), nil)
+//
+// The environment is read when EnvLabelsFromEnviron is called, not as events are logged.
+func EnvLabelsFromEnviron(keys ...string) map[string]string {
+	labels := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			labels[strings.ToLower(k)] = v
+		}
+	}
+	return labels
+}
+
+// DefaultEnvLabels returns EnvLabelsFromEnviron for the variables in DefaultEnvLabelVariables.
+func DefaultEnvLabels() map[string]string {
+	return EnvLabelsFromEnviron(DefaultEnvLabelVariables...)
+}
@@ -0,0 +1,43 @@
+package slog
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvLabelsFromEnviron(t *testing.T) {
+	defer setenv(t, "DEPLOY_ENV", "prod")()
+	defer setenv(t, "REGION", "")()
+	defer setenv(t, "SERVICE_NAME", "widgets")()
+
+	assert.Equal(t, map[string]string{
+		"deploy_env":   "prod",
+		"service_name": "widgets",
+	}, EnvLabelsFromEnviron("DEPLOY_ENV", "REGION", "SERVICE_NAME", "SLOG_TEST_UNSET"))
+	assert.Empty(t, EnvLabelsFromEnviron())
+
+	next := NewInMemoryLogger()
+	logger := NewFieldsLogger(next, DefaultEnvLabels(), nil)
+	logger.Log(Eventf(InfoSeverity, context.Background(), "foo"))
+	require.Len(t, next.Events(), 1)
+	assert.Equal(t, "prod", next.Events()[0].Labels["deploy_env"])
+	assert.NotContains(t, next.Events()[0].Labels, "region")
+}
+
+// setenv sets an environment variable, returning a function which restores its previous value. Unlike t.Setenv, it
+// works on the versions of Go before 1.17 which the package supports.
+func setenv(t *testing.T, key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
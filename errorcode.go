@@ -0,0 +1,197 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	// ErrorCodeLabelKey is the label key for the code of a logged ErrorCoder.
+	ErrorCodeLabelKey = "error_code"
+	// ErrorParamsMetadataKey is the metadata key for the params of a logged ErrorCoder.
+	ErrorParamsMetadataKey = "error_params"
+)
+
+// clientErrorCodes are the prefixes of error codes, as used by terrors, which describe a problem with the caller's
+// request, rather than a failure of the service.
+var clientErrorCodes = map[string]bool{
+	"bad_request":         true,
+	"forbidden":           true,
+	"not_found":           true,
+	"precondition_failed": true,
+	"unauthorized":        true,
+}
+
+// An ErrorCoder is an error with a machine-readable code. When an event's error is (or wraps) an ErrorCoder, its code
+// is promoted to the ErrorCodeLabelKey label, so that it can be indexed.
+//
+// An ErrorCoder may also carry params, by implementing ErrorParams() map[string]string; these are kept as structured
+// metadata under ErrorParamsMetadataKey. To have context params attached by an ErrorParamsLogger, it must also
+// implement WithErrorParams(params map[string]string) error, returning a copy of itself with its params replaced.
+//
+// *terrors.Error doesn't implement ErrorCoder, but is recognised explicitly (by its package path and type name, as
+// this package doesn't depend on terrors), and treated as if it did: its Code and Params fields are used. Errors of
+// other types are only recognised if they implement ErrorCoder, whatever fields they have.
+type ErrorCoder interface {
+	error
+	ErrorCode() string
+}
+
+// errorParamsProvider is implemented by ErrorCoders which carry params.
+type errorParamsProvider interface {
+	ErrorParams() map[string]string
+}
+
+// errorParamsSetter is implemented by ErrorCoders which can be copied with different params.
+type errorParamsSetter interface {
+	WithErrorParams(params map[string]string) error
+}
+
+// terrorsPkgPath and terrorsTypeName identify terrors.Error. They are variables so that tests can substitute another
+// type, as terrors isn't a dependency of this package.
+var (
+	terrorsPkgPath  = "github.com/monzo/terrors"
+	terrorsTypeName = "Error"
+)
+
+var (
+	stringType    = reflect.TypeOf("")
+	stringMapType = reflect.TypeOf(map[string]string(nil))
+)
+
+// terrorAdapter adapts a *terrors.Error to ErrorCoder. v is the struct it points to.
+type terrorAdapter struct {
+	error
+	v reflect.Value
+}
+
+func (t terrorAdapter) ErrorCode() string {
+	return t.v.FieldByName("Code").String()
+}
+
+func (t terrorAdapter) ErrorParams() map[string]string {
+	return t.v.FieldByName("Params").Interface().(map[string]string)
+}
+
+// WithErrorParams returns a copy of the *terrors.Error with its params replaced.
+func (t terrorAdapter) WithErrorParams(params map[string]string) error {
+	cp := reflect.New(t.v.Type())
+	cp.Elem().Set(t.v)
+	cp.Elem().FieldByName("Params").Set(reflect.ValueOf(params))
+	return cp.Interface().(error)
+}
+
+// asTerror adapts err to ErrorCoder if it is a non-nil *terrors.Error.
+func asTerror(err error) (ErrorCoder, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, false
+	}
+	t := v.Type().Elem()
+	if t.Kind() != reflect.Struct || t.PkgPath() != terrorsPkgPath || t.Name() != terrorsTypeName {
+		return nil, false
+	}
+	// Check the fields are still what they were, in case a future version of terrors changes them
+	if f, ok := t.FieldByName("Code"); !ok || f.Type != stringType {
+		return nil, false
+	}
+	if f, ok := t.FieldByName("Params"); !ok || f.Type != stringMapType {
+		return nil, false
+	}
+	return terrorAdapter{error: err, v: v.Elem()}, true
+}
+
+// asErrorCoder returns err as an ErrorCoder, if it is one or is a *terrors.Error.
+func asErrorCoder(err error) (ErrorCoder, bool) {
+	if coder, ok := err.(ErrorCoder); ok {
+		// A typed nil can't be asked for its code
+		if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, false
+		}
+		return coder, true
+	}
+	return asTerror(err)
+}
+
+// errorCodeAndParams returns the code and params of err if it is an ErrorCoder (or a *terrors.Error).
+func errorCodeAndParams(err error) (string, map[string]string, bool) {
+	coder, ok := asErrorCoder(err)
+	if !ok {
+		return "", nil, false
+	}
+	var params map[string]string
+	if p, ok := coder.(errorParamsProvider); ok {
+		params = p.ErrorParams()
+	}
+	return coder.ErrorCode(), params, true
+}
+
+// findErrorCode is like errorCodeAndParams, but searches err's chain, so that errors which have been wrapped on their
+// way up are still recognised. An ErrorCoder anywhere in the chain takes precedence over a *terrors.Error.
+func findErrorCode(err error) (string, map[string]string, bool) {
+	var coder ErrorCoder
+	if errors.As(err, &coder) {
+		if code, params, ok := errorCodeAndParams(coder); ok {
+			return code, params, true
+		}
+	}
+	if terr, ok := findTerror(err); ok {
+		return errorCodeAndParams(terr)
+	}
+	return "", nil, false
+}
+
+// findTerror searches err's chain for a *terrors.Error. errors.As can't be used to find one, as that needs its type.
+func findTerror(err error) (error, bool) {
+	for err != nil {
+		if _, ok := asTerror(err); ok {
+			return err, true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if terr, ok := findTerror(e); ok {
+					return terr, true
+				}
+			}
+			return nil, false
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// withErrorParams returns a copy of err with the given params added to its own, if it is an ErrorCoder which can be
+// copied with different params (or a *terrors.Error). Existing params take precedence. Neither err nor its params map
+// is modified.
+func withErrorParams(err error, params map[string]string) (error, bool) {
+	coder, ok := asErrorCoder(err)
+	if !ok {
+		return err, false
+	}
+	setter, ok := coder.(errorParamsSetter)
+	if !ok {
+		return err, false
+	}
+	_, existing, _ := errorCodeAndParams(coder)
+	return setter.WithErrorParams(mergeLabels(mergeLabels(nil, existing), params)), true
+}
+
+// SeverityForError classifies an error into the severity it should be logged with when the severity isn't given
+// explicitly, as by FromError. Cancellations, deadlines and ErrorCoders whose codes describe a bad request (such as
+// bad_request or not_found) are warnings; anything else is an error. Wrapped errors are classified by the first error
+// in the chain which is recognised.
+func SeverityForError(err error) Severity {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return WarnSeverity
+	}
+	if code, _, ok := findErrorCode(err); ok && clientErrorCodes[strings.SplitN(code, ".", 2)[0]] {
+		return WarnSeverity
+	}
+	return ErrorSeverity
+}
@@ -0,0 +1,191 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// terrorsError is an ErrorCoder with params, like *terrors.Error.
+type terrorsError struct {
+	Code    string
+	Message string
+	Params  map[string]string
+}
+
+func (e *terrorsError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func (e *terrorsError) ErrorCode() string {
+	return e.Code
+}
+
+func (e *terrorsError) ErrorParams() map[string]string {
+	return e.Params
+}
+
+func (e *terrorsError) WithErrorParams(params map[string]string) error {
+	cp := *e
+	cp.Params = params
+	return &cp
+}
+
+// lookalikeError has Code and Params fields, but doesn't implement ErrorCoder.
+type lookalikeError struct {
+	Code   string
+	Params map[string]string
+}
+
+func (e *lookalikeError) Error() string {
+	return "lookalike"
+}
+
+// codeOnlyError is an ErrorCoder without params.
+type codeOnlyError string
+
+func (e codeOnlyError) Error() string {
+	return string(e)
+}
+
+func (e codeOnlyError) ErrorCode() string {
+	return string(e)
+}
+
+func TestEventfErrorCode(t *testing.T) {
+	err := &terrorsError{
+		Code:    "bad_request",
+		Message: "missing widget",
+		Params:  map[string]string{"widget_id": "w1"},
+	}
+	e := Eventf(ErrorSeverity, context.Background(), "Failed to load widget", err)
+
+	assert.Equal(t, err, e.Error)
+	assert.Equal(t, map[string]string{ErrorCodeLabelKey: "bad_request"}, e.Labels)
+	assert.Equal(t, map[string]string{"widget_id": "w1"}, e.Metadata[ErrorParamsMetadataKey])
+}
+
+func TestEventfErrorCodeUnrecognised(t *testing.T) {
+	e := Eventf(ErrorSeverity, context.Background(), "Failed to load widget", errors.New("boom"))
+	assert.Nil(t, e.Labels)
+	assert.NotContains(t, e.Metadata, ErrorParamsMetadataKey)
+
+	var nilErr *terrorsError
+	e = Eventf(ErrorSeverity, context.Background(), "Failed to load widget", nilErr)
+	assert.Nil(t, e.Labels)
+
+	// Errors are recognised by implementing ErrorCoder, not by their fields
+	e = Eventf(ErrorSeverity, context.Background(), "Failed to load widget", &lookalikeError{
+		Code:   "bad_request",
+		Params: map[string]string{"widget_id": "w1"},
+	})
+	assert.Nil(t, e.Labels)
+	assert.NotContains(t, e.Metadata, ErrorParamsMetadataKey)
+	assert.Equal(t, ErrorSeverity, SeverityForError(&lookalikeError{Code: "bad_request"}))
+}
+
+func TestEventfErrorCodeWithoutParams(t *testing.T) {
+	e := Eventf(ErrorSeverity, context.Background(), "Failed to load widget", codeOnlyError("not_found.widget"))
+	assert.Equal(t, map[string]string{ErrorCodeLabelKey: "not_found.widget"}, e.Labels)
+	assert.NotContains(t, e.Metadata, ErrorParamsMetadataKey)
+}
+
+func TestEventfWrappedErrorCode(t *testing.T) {
+	terr := &terrorsError{
+		Code:    "bad_request",
+		Message: "missing widget",
+		Params:  map[string]string{"widget_id": "w1"},
+	}
+	err := fmt.Errorf("handling request: %w", fmt.Errorf("loading widget: %w", fmt.Errorf("querying: %w", terr)))
+	e := Eventf(ErrorSeverity, context.Background(), "Failed to load widget", err)
+
+	assert.Equal(t, err, e.Error)
+	assert.Equal(t, map[string]string{ErrorCodeLabelKey: "bad_request"}, e.Labels)
+	assert.Equal(t, map[string]string{"widget_id": "w1"}, e.Metadata[ErrorParamsMetadataKey])
+
+	// The first terror in a joined error is used
+	err = fmt.Errorf("wrapped: %w", joinedError{
+		errors.New("boom"),
+		fmt.Errorf("wrapped: %w", terr),
+		&terrorsError{Code: "internal_service"},
+	})
+	e = Eventf(ErrorSeverity, context.Background(), "Failed to load widget", err)
+	assert.Equal(t, map[string]string{ErrorCodeLabelKey: "bad_request"}, e.Labels)
+}
+
+func TestSeverityForError(t *testing.T) {
+	assert.Equal(t, WarnSeverity, SeverityForError(context.Canceled))
+	assert.Equal(t, WarnSeverity, SeverityForError(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+	assert.Equal(t, WarnSeverity, SeverityForError(&terrorsError{Code: "bad_request"}))
+	assert.Equal(t, WarnSeverity, SeverityForError(&terrorsError{Code: "not_found.widget"}))
+	assert.Equal(t, WarnSeverity, SeverityForError(fmt.Errorf("wrapped: %w", &terrorsError{Code: "forbidden"})))
+	assert.Equal(t, ErrorSeverity, SeverityForError(&terrorsError{Code: "internal_service"}))
+	assert.Equal(t, ErrorSeverity, SeverityForError(&terrorsError{Code: "bad_requester"}))
+	assert.Equal(t, ErrorSeverity, SeverityForError(errors.New("boom")))
+	assert.Equal(t, ErrorSeverity, SeverityForError(nil))
+}
+
+// fakeTerror has the fields of terrors.Error which are used, and stands in for it while useFakeTerrors is in effect,
+// as terrors isn't a dependency of this package.
+type fakeTerror struct {
+	Code    string
+	Message string
+	Params  map[string]string
+	cause   error
+}
+
+func (e *fakeTerror) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// useFakeTerrors makes fakeTerror be recognised as terrors.Error, returning a function which restores the default.
+func useFakeTerrors() func() {
+	oldPkgPath, oldTypeName := terrorsPkgPath, terrorsTypeName
+	terrorsPkgPath, terrorsTypeName = reflect.TypeOf(fakeTerror{}).PkgPath(), "fakeTerror"
+	return func() {
+		terrorsPkgPath, terrorsTypeName = oldPkgPath, oldTypeName
+	}
+}
+
+func TestEventfTerror(t *testing.T) {
+	terr := &fakeTerror{Code: "bad_request.missing_widget", Params: map[string]string{"widget_id": "w1"}}
+
+	// Without recognition, it has no code, as it doesn't implement ErrorCoder
+	e := Eventf(ErrorSeverity, context.Background(), "Failed to load widget", terr)
+	assert.Nil(t, e.Labels)
+	assert.Equal(t, ErrorSeverity, SeverityForError(terr))
+
+	defer useFakeTerrors()()
+	e = Eventf(ErrorSeverity, context.Background(), "Failed to load widget", fmt.Errorf("loading: %w", terr))
+	assert.Equal(t, map[string]string{ErrorCodeLabelKey: "bad_request.missing_widget"}, e.Labels)
+	assert.Equal(t, map[string]string{"widget_id": "w1"}, e.Metadata[ErrorParamsMetadataKey])
+	assert.Equal(t, WarnSeverity, SeverityForError(fmt.Errorf("loading: %w", terr)))
+
+	var nilErr *fakeTerror
+	assert.Nil(t, Eventf(ErrorSeverity, context.Background(), "Failed", nilErr).Labels)
+	assert.Nil(t, Eventf(ErrorSeverity, context.Background(), "Failed", &lookalikeError{Code: "bad_request"}).Labels)
+}
+
+func TestErrorParamsLoggerTerror(t *testing.T) {
+	defer useFakeTerrors()()
+	next := NewInMemoryLogger()
+	logger := NewErrorParamsLogger(next)
+
+	cause := errors.New("cause")
+	terr := &fakeTerror{Code: "internal", Message: "failed", Params: map[string]string{"widget_id": "w1"}, cause: cause}
+	ctx := WithParams(context.Background(), map[string]string{"request_id": "r1", "widget_id": "context"})
+	logger.Log(Eventf(ErrorSeverity, ctx, "Failed", terr))
+
+	require.Len(t, next.Events(), 1)
+	if decorated, ok := next.Events()[0].Error.(*fakeTerror); assert.True(t, ok) {
+		assert.Equal(t, map[string]string{"request_id": "r1", "widget_id": "w1"}, decorated.Params)
+		assert.Equal(t, "internal", decorated.Code)
+		assert.Equal(t, cause, decorated.cause)
+	}
+	assert.Equal(t, map[string]string{"widget_id": "w1"}, terr.Params)
+}
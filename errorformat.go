@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+var (
+	errorFormatter  func(error) string
+	errorFormatterM sync.RWMutex
+)
+
+// SetErrorFormatter sets a function which renders errors as strings, so that teams can standardise how errors appear,
+// for example to include error codes. It is used when an error is a formatting operand of Eventf for the %v, %s and
+// %q verbs, and for the string form of an event's Error in Event.String and its JSON and ECS encodings. Other
+// operands, and errors formatted with other verbs (such as %+v or %#v), are unaffected. By default, and if f is nil,
+// errors are rendered as by %v.
+func SetErrorFormatter(f func(error) string) {
+	errorFormatterM.Lock()
+	defer errorFormatterM.Unlock()
+	errorFormatter = f
+}
+
+func currentErrorFormatter() func(error) string {
+	errorFormatterM.RLock()
+	defer errorFormatterM.RUnlock()
+	return errorFormatter
+}
+
+// formatError returns the string form of err, using the formatter set with SetErrorFormatter if there is one.
+func formatError(err error) string {
+	if f := currentErrorFormatter(); f != nil {
+		return f(err)
+	}
+	return err.Error()
+}
+
+// formatErrorOperands returns the params with any errors wrapped so that they are rendered by the formatter set with
+// SetErrorFormatter when formatted with msg. Errors which msg uses with %T or %p, which describe the operand itself, are
+// left alone. The params are returned unchanged if there is no formatter, or no errors among them.
+func formatErrorOperands(msg string, params []interface{}) []interface{} {
+	f := currentErrorFormatter()
+	if f == nil {
+		return params
+	}
+
+	var verbatim map[int]bool
+	scanFmtOperands(msg, false, func(pos, arg int, verb rune) {
+		if verb == 'T' || verb == 'p' {
+			if verbatim == nil {
+				verbatim = map[int]bool{}
+			}
+			verbatim[arg] = true
+		}
+	})
+
+	result, copied := params, false
+	for i, param := range params {
+		err, ok := param.(error)
+		if !ok || verbatim[i] {
+			continue
+		}
+		if !copied {
+			result, copied = append([]interface{}(nil), params...), true
+		}
+		result[i] = formattedError{err, f}
+	}
+	return result
+}
+
+// formattedError renders an error with a custom formatter for the %v, %s and %q verbs, and as the error itself would be
+// for any others.
+type formattedError struct {
+	err error
+	f   func(error) string
+}
+
+func (e formattedError) Format(s fmt.State, verb rune) {
+	format := "%"
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		format += strconv.Itoa(width)
+	}
+	if precision, ok := s.Precision(); ok {
+		format += "." + strconv.Itoa(precision)
+	}
+	format += string(verb)
+
+	switch {
+	case (verb == 'v' && !s.Flag('+') && !s.Flag('#')) || verb == 's' || verb == 'q':
+		fmt.Fprintf(s, format, e.f(e.err))
+	default:
+		fmt.Fprintf(s, format, e.err)
+	}
+}
@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verboseError has a %+v form, like errors with stack traces.
+type verboseError struct{}
+
+func (verboseError) Error() string {
+	return "verbose"
+}
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		fmt.Fprint(s, "verbose with detail")
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+func TestSetErrorFormatter(t *testing.T) {
+	SetErrorFormatter(func(err error) string {
+		if code, _, ok := findErrorCode(err); ok {
+			return "[" + code + "] " + err.Error()
+		}
+		return "<" + err.Error() + ">"
+	})
+	defer SetErrorFormatter(nil)
+	ctx := context.Background()
+	terr := &terrorsError{Code: "bad_request", Message: "missing widget"}
+
+	e := Eventf(ErrorSeverity, ctx, "Failed: %v", terr)
+	assert.Equal(t, "Failed: [bad_request] bad_request: missing widget", e.Message)
+	assert.Equal(t, terr, e.Error)
+	assert.Contains(t, e.String(), "error=[bad_request] bad_request: missing widget ")
+
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+	decoded := Event{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "[bad_request] bad_request: missing widget", decoded.Error.(map[string]interface{})["message"])
+
+	boom := errors.New("boom")
+	cases := map[string]string{
+		"%v":    "<boom>",
+		"%s":    "<boom>",
+		"%q":    `"<boom>"`,
+		"%8s|":  "  <boom>|",
+		"%-8v|": "<boom>  |",
+		"%T":    "*errors.errorString",
+		"%d":    fmt.Sprintf("%d", boom),
+	}
+	for format, expected := range cases {
+		assert.Equal(t, expected, Eventf(ErrorSeverity, ctx, format, boom).Message, format)
+	}
+
+	// Only error operands are affected
+	assert.Equal(t, "boom <boom>", Eventf(ErrorSeverity, ctx, "%s %s", "boom", boom).Message)
+	// Verbs asking for detail are passed through to the error
+	assert.Equal(t, "verbose with detail", Eventf(ErrorSeverity, ctx, "%+v", verboseError{}).Message)
+	assert.Equal(t, "<verbose>", Eventf(ErrorSeverity, ctx, "%v", verboseError{}).Message)
+}
+
+func TestSetErrorFormatterDefault(t *testing.T) {
+	e := Eventf(ErrorSeverity, context.Background(), "Failed: %v", errors.New("boom"))
+	assert.Equal(t, "Failed: boom", e.Message)
+	assert.Contains(t, e.String(), "error=boom ")
+}
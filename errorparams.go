@@ -0,0 +1,37 @@
+package slog
+
+// An ErrorParamsLogger attaches the params of an event's context to its error, if it is an ErrorCoder which supports
+// it (see ErrorCoder), before forwarding it to another Logger. This means error reports built from the error carry the
+// same params as the event.
+type ErrorParamsLogger struct {
+	next Logger
+}
+
+// NewErrorParamsLogger creates an ErrorParamsLogger which forwards events to next.
+func NewErrorParamsLogger(next Logger) *ErrorParamsLogger {
+	return &ErrorParamsLogger{
+		next: next,
+	}
+}
+
+// Log the events to the underlying logger, with context params merged into their errors. Params already on an error
+// take precedence. The error is copied rather than modified, as it may be shared with other code.
+func (l *ErrorParamsLogger) Log(evs ...Event) {
+	decorated := make([]Event, len(evs))
+	for i, e := range evs {
+		if err, ok := e.Error.(error); ok {
+			if params := Params(e.Context); len(params) > 0 {
+				if decorated, ok := withErrorParams(err, params); ok {
+					e.Error = decorated
+				}
+			}
+		}
+		decorated[i] = e
+	}
+	l.next.Log(decorated...)
+}
+
+// Flush the underlying logger.
+func (l *ErrorParamsLogger) Flush() error {
+	return l.next.Flush()
+}
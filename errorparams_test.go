@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorParamsLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewErrorParamsLogger(next)
+
+	ctx := WithParams(context.Background(), map[string]string{
+		"request_id": "r1",
+		"widget_id":  "context",
+	})
+	err := &terrorsError{
+		Code:    "bad_request",
+		Message: "missing widget",
+		Params:  map[string]string{"widget_id": "w1"},
+	}
+	plain := errors.New("plain")
+	codeOnly := codeOnlyError("bad_request")
+	logger.Log(
+		Eventf(ErrorSeverity, ctx, "terror", err),
+		Eventf(ErrorSeverity, ctx, "plain", plain),
+		Eventf(ErrorSeverity, context.Background(), "no params", err),
+		Eventf(ErrorSeverity, ctx, "unsupported", codeOnly))
+
+	events := next.Events()
+	require.Len(t, events, 4)
+	if decorated, ok := events[0].Error.(*terrorsError); assert.True(t, ok) {
+		assert.Equal(t, "bad_request", decorated.Code)
+		assert.Equal(t, map[string]string{
+			"request_id": "r1",
+			"widget_id":  "w1",
+		}, decorated.Params)
+	}
+	assert.Equal(t, plain, events[1].Error)
+	assert.True(t, err == events[2].Error)
+	assert.Equal(t, codeOnly, events[3].Error)
+
+	// The original error is untouched
+	assert.Equal(t, map[string]string{"widget_id": "w1"}, err.Params)
+}
@@ -2,10 +2,12 @@ package slog
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
-
-	uuid "github.com/nu7hatch/gouuid"
 )
 
 type Severity int
@@ -21,7 +23,40 @@ const (
 	CriticalSeverity Severity = 6
 )
 
+var (
+	severityNames  map[Severity]string
+	severityNamesM sync.RWMutex
+)
+
+// SetSeverityNames overrides the names returned by Severity.String, and so used in all formatted output, for the
+// given severities. Severities not in the map keep their default names. ParseSeverity accepts the overridden names as
+// well as the defaults.
+func SetSeverityNames(names map[Severity]string) {
+	copied := make(map[Severity]string, len(names))
+	for sev, name := range names {
+		copied[sev] = name
+	}
+
+	severityNamesM.Lock()
+	defer severityNamesM.Unlock()
+	severityNames = copied
+}
+
+// ResetSeverityNames restores the default severity names.
+func ResetSeverityNames() {
+	severityNamesM.Lock()
+	defer severityNamesM.Unlock()
+	severityNames = nil
+}
+
 func (s Severity) String() string {
+	severityNamesM.RLock()
+	name, ok := severityNames[s]
+	severityNamesM.RUnlock()
+	if ok {
+		return name
+	}
+
 	switch s {
 	case CriticalSeverity:
 		return "CRITICAL"
@@ -38,18 +73,79 @@ func (s Severity) String() string {
 	}
 }
 
+// ParseSeverity returns the Severity with the given name. The name is matched case-insensitively.
+func ParseSeverity(name string) (Severity, error) {
+	severityNamesM.RLock()
+	for sev, overridden := range severityNames {
+		if strings.EqualFold(name, overridden) {
+			severityNamesM.RUnlock()
+			return sev, nil
+		}
+	}
+	severityNamesM.RUnlock()
+
+	switch strings.ToUpper(name) {
+	case "CRITICAL":
+		return CriticalSeverity, nil
+	case "ERROR":
+		return ErrorSeverity, nil
+	case "WARN":
+		return WarnSeverity, nil
+	case "INFO":
+		return InfoSeverity, nil
+	case "DEBUG":
+		return DebugSeverity, nil
+	case "TRACE":
+		return TraceSeverity, nil
+	default:
+		return 0, fmt.Errorf("unknown severity %q", name)
+	}
+}
+
+// MarshalJSON encodes the severity as its name, e.g. "ERROR".
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a severity from its name. For backwards-compatibility, the integer form is also accepted.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		sev, err := ParseSeverity(name)
+		if err != nil {
+			return err
+		}
+		*s = sev
+		return nil
+	}
+
+	var value int
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid severity %s", data)
+	}
+	*s = Severity(value)
+	return nil
+}
+
 type logMetadataProvider interface {
 	LogMetadata() map[string]string
 }
 
+type logLabelsProvider interface {
+	LogLabels() map[string]string
+}
+
 // An Event is a discrete logging event
 type Event struct {
-	Context         context.Context `json:"-"`
-	Id              string          `json:"id"`
-	Timestamp       time.Time       `json:"timestamp"`
-	Severity        Severity        `json:"severity"`
-	Message         string          `json:"message"`
-	OriginalMessage string          `json:"-"`
+	Context   context.Context `json:"-"`
+	Id        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	// Sequence is a strictly increasing number assigned to each event built by Eventf, which totally orders the
+	// process's events even if their timestamps are identical, or go backwards as the clock is adjusted.
+	Sequence        uint64   `json:"sequence,omitempty"`
+	Severity        Severity `json:"severity"`
+	Message         string   `json:"message"`
+	OriginalMessage string   `json:"-"`
 	// Metadata are structured key-value pairs which describe the event.
 	Metadata map[string]interface{} `json:"meta,omitempty"`
 	// Labels, like Metadata, are key-value pairs which describe the event. Unlike Metadata, these are intended to be
@@ -62,32 +158,317 @@ func (e Event) String() string {
 	errorMessage := ""
 	if e.Error != nil {
 		if err, ok := e.Error.(error); ok {
-			errorMessage = err.Error()
+			errorMessage = formatError(err)
 		}
 	}
 
 	return fmt.Sprintf("[%s] %s %s (error=%v metadata=%v labels=%v id=%s)", e.Timestamp.Format(TimeFormat),
-		e.Severity.String(), e.Message, errorMessage, e.Metadata, e.Labels, e.Id)
+		e.Severity.String(), e.Message, errorMessage, displayMetadata(e.Metadata), e.Labels, e.Id)
+}
+
+// maxDisplayDepth is the depth to which nested metadata values are rendered by Event.String. Deeper values are
+// elided.
+const maxDisplayDepth = 5
+
+// elided replaces nested metadata values beyond maxDisplayDepth.
+const elided = "..."
+
+// displayMetadata returns a copy of the metadata for formatting, in which nested maps and slices are replaced by
+// their JSON encoding, which is more readable than Go's default formatting, and byte slices by their BytesEncoding.
+func displayMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		result[k] = v
+		if b, ok := v.([]byte); ok {
+			result[k] = encodeBytes(b)
+			continue
+		}
+		if !isNested(reflect.ValueOf(v)) {
+			continue
+		}
+		if b, err := json.Marshal(limitDepth(reflect.ValueOf(v), maxDisplayDepth)); err == nil {
+			result[k] = jsonFragment(b)
+		}
+	}
+	return result
+}
+
+// jsonFragment is JSON which is formatted as-is by fmt.
+type jsonFragment string
+
+func isNested(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		// Byte slices are left to be formatted as strings
+		return v.Type().Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// limitDepth returns v with nested maps and slices below the given depth replaced by elided.
+func limitDepth(v reflect.Value, depth int) interface{} {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if !isNested(v) {
+		if b, ok := v.Interface().([]byte); ok {
+			return encodeBytes(b)
+		}
+		return v.Interface()
+	}
+	if depth <= 0 {
+		return elided
+	}
+
+	if v.Kind() == reflect.Map {
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = limitDepth(iter.Value(), depth-1)
+		}
+		return result
+	}
+	result := make([]interface{}, v.Len())
+	for i := range result {
+		result[i] = limitDepth(v.Index(i), depth-1)
+	}
+	return result
+}
+
+// Fields returns a flattened view of the event, suitable for adapters to backends which accept a single map of
+// fields. The map contains the well-known keys "id", "timestamp", "severity" and "message" (and "error" if the event
+// has one), every metadata entry, and every label namespaced as "label.<key>".
+//
+// Where keys collide, well-known keys take precedence over labels, which take precedence over metadata.
+func (e Event) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(e.Metadata)+len(e.Labels)+5)
+	for k, v := range e.Metadata {
+		fields[k] = v
+	}
+	for k, v := range e.Labels {
+		fields["label."+k] = v
+	}
+
+	fields["id"] = e.Id
+	fields["timestamp"] = e.Timestamp
+	fields["severity"] = e.Severity.String()
+	fields["message"] = e.Message
+	if e.Error != nil {
+		fields[ErrorMetadataKey] = e.Error
+	}
+	return fields
+}
+
+// WithMetadata returns a copy of the event with the given metadata entry added, replacing any existing value for the
+// key. The event's metadata map is copied rather than modified.
+func (e Event) WithMetadata(k string, v interface{}) Event {
+	metadata := make(map[string]interface{}, len(e.Metadata)+1)
+	for mk, mv := range e.Metadata {
+		metadata[mk] = mv
+	}
+	metadata[k] = v
+	e.Metadata = metadata
+	return e
+}
+
+// WithLabel returns a copy of the event with the given label added, replacing any existing value for the key. The
+// event's labels map is copied rather than modified.
+func (e Event) WithLabel(k, v string) Event {
+	labels := make(map[string]string, len(e.Labels)+1)
+	for lk, lv := range e.Labels {
+		labels[lk] = lv
+	}
+	labels[k] = v
+	e.Labels = labels
+	return e
+}
+
+// wireError is the JSON form of an event's error, for errors which can't otherwise be serialized.
+type wireError struct {
+	Code    string            `json:"code,omitempty"`
+	Message string            `json:"message"`
+	Params  map[string]string `json:"params,omitempty"`
+	// Errors contains the constituent errors of a joined error, such as one created by errors.Join.
+	Errors []wireError `json:"errors,omitempty"`
+}
+
+func newWireError(err error) wireError {
+	werr := wireError{Message: formatError(err)}
+	if code, params, ok := errorCodeAndParams(err); ok {
+		werr.Code, werr.Params = code, params
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if e != nil {
+				werr.Errors = append(werr.Errors, newWireError(e))
+			}
+		}
+	}
+	return werr
+}
+
+// MarshalJSON encodes the event. Go errors mostly have no exported fields, so would be encoded as {}; instead, an
+// Error which doesn't implement json.Marshaler is encoded as an object containing its message, its code and params
+// for ErrorCoders, and each of its constituent errors for joined errors. When decoded, this is a
+// map[string]interface{}.
+//
+// An event is never lost because part of it can't be encoded: metadata values (or an Error) which can't be encoded,
+// such as channels and funcs, are replaced by a placeholder describing their type.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event // Has no methods, so doesn't recurse
+	wire := event(e)
+	wire.Metadata = encodeBytesMetadata(e.Metadata)
+	if err, ok := e.Error.(error); ok {
+		if _, ok := err.(json.Marshaler); !ok {
+			wire.Error = newWireError(err)
+		}
+	}
+
+	b, err := json.Marshal(wire)
+	if err == nil {
+		return b, nil
+	}
+	// Only pay for finding the culprits once encoding has failed
+	wire.Metadata = serializableMetadata(wire.Metadata)
+	if _, err := json.Marshal(wire.Error); err != nil {
+		wire.Error = unserializable(wire.Error)
+	}
+	return json.Marshal(wire)
+}
+
+// serializableMetadata returns a copy of the metadata in which values that can't be encoded as JSON are replaced by a
+// placeholder. Nested metadata is handled recursively, so only the offending values are replaced.
+func serializableMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		result[k] = serializableValue(v)
+	}
+	return result
+}
+
+func serializableValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return serializableMetadata(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = serializableValue(elem)
+		}
+		return result
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return unserializable(v)
+	}
+	return v
+}
+
+// unserializable returns the placeholder for a value which can't be encoded as JSON.
+func unserializable(v interface{}) string {
+	return fmt.Sprintf("<unserializable: %T>", v)
 }
 
 // Eventf constructs an event from the given message string and formatting operands. Optionally, event metadata
 // (map[string]interface{}, or map[string]string) can be provided as a final argument.
 func Eventf(sev Severity, ctx context.Context, msg string, params ...interface{}) Event {
+	return eventf(sev, ctx, msg, params, eventOptions{})
+}
+
+// EventfWithMetadata is like Eventf, but takes the event's metadata separately, so that every param is a formatting
+// operand. Eventf guesses that trailing params beyond those the format string expects are metadata, which can be
+// wrong; EventfWithMetadata never treats a param as metadata, even if it is a map, so it is never misclassified. As
+// with Eventf, errors among the params are still used as the event's Error.
+func EventfWithMetadata(
+	sev Severity, ctx context.Context, metadata map[string]interface{}, msg string, params ...interface{}) Event {
+	return eventf(sev, ctx, msg, params, eventOptions{explicit: true, metadata: metadata})
+}
+
+// EventfAt is like Eventf, but the event is timestamped with ts rather than the current time. This is intended for
+// tools which replay or import historical events, and adapters from other logging libraries, so they keep their
+// original times. As with Eventf, the timestamp is expressed in the location set by SetTimezone. If ts is zero, the
+// current time is used.
+func EventfAt(ts time.Time, sev Severity, ctx context.Context, msg string, params ...interface{}) Event {
+	return eventf(sev, ctx, msg, params, eventOptions{at: ts})
+}
+
+// NewEvent constructs a bare event, with its Id, Timestamp and Sequence assigned as by Eventf, for authors of adapters
+// from other logging libraries, which set the event's Metadata, Labels and Error themselves. Unlike Eventf, msg is
+// never treated as a format string, and nothing else is added: no metadata from the context, caller or severity
+// defaults.
+func NewEvent(sev Severity, ctx context.Context, msg string) Event {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timestamp := eventTime()
+	return Event{
+		Context:         ctx,
+		Id:              newEventID(timestamp),
+		Timestamp:       timestamp,
+		Sequence:        nextSequence(),
+		Severity:        sev,
+		Message:         msg,
+		OriginalMessage: msg,
+	}
+}
+
+// eventOptions are the variations on Eventf.
+type eventOptions struct {
+	// explicit is set if all params are formatting operands, and the inline metadata is given by metadata, rather than
+	// being extracted from the params.
+	explicit bool
+	metadata map[string]interface{}
+	// at is the event's timestamp, if it isn't the current time.
+	at time.Time
+	// template is set if the message is a template with named placeholders (see Eventft), rather than a format string.
+	template bool
+}
+
+// eventf implements Eventf and its variations.
+func eventf(sev Severity, ctx context.Context, msg string, params []interface{}, opts eventOptions) Event {
 	originalMessage := msg
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	id, err := uuid.NewV4()
-	if err != nil {
-		return Event{}
+	timestamp := eventTime()
+	if !opts.at.IsZero() {
+		timestamp = inEventLocation(opts.at)
 	}
+	seq := nextSequence()
+	id := newEventID(timestamp)
 
-	metadata := map[string]interface{}(nil)
+	providerMetadata, inlineMetadata := map[string]interface{}(nil), map[string]interface{}(nil)
+	labels := map[string]string(nil)
 	var errParam error
+	// formatErr describes a mismatch between the format string and the params, if there is one
+	var formatErr error
+	// Without the heuristic to fall back on, any mismatch between the format string and the params is a mistake
+	if opts.explicit && !opts.template {
+		if formatErr = ValidateFormat(msg, len(params)); formatErr != nil {
+			if formatValidationEnabled() {
+				reportInvalidFormat(ctx, msg, len(params))
+			}
+			strictPanic(formatErr)
+		}
+	}
 	if len(params) > 0 {
 
 		fmtOperands := countFmtOperands(msg)
+		if opts.explicit {
+			fmtOperands = len(params)
+		}
 
 		// If we have been provided with more params than we have formatting arguments, then we have
 		// been given some metadata.
@@ -107,52 +488,161 @@ func Eventf(sev Severity, ctx context.Context, msg string, params ...interface{}
 		if extraParamCount < 0 {
 			hasFormatOverflow = true
 			extraParamCount = len(params)
+			formatErr = ValidateFormat(msg, len(params))
+			if formatValidationEnabled() {
+				reportInvalidFormat(ctx, msg, len(params))
+			}
+			strictPanic(formatErr)
 		}
 
-		// Attempt to pull metadata and errors from any params.
-		// This means that we'll still extract errors and metadata, even if it
-		// is going to be interpolated into the message. This may result in some
-		// duplication, but always gives us the most structured data possible.
-		if len(params) > 0 {
-			metadata = mergeMetadata(metadata, metadataFromParams(params))
+		// Most events only have params which are interpolated, so the extraction of structured data from them is
+		// skipped unless there is something to extract.
+		if hasStructuredParams(params) {
+			// Attempt to pull metadata and errors from any params.
+			// This means that we'll still extract errors and metadata, even if it
+			// is going to be interpolated into the message. This may result in some
+			// duplication, but always gives us the most structured data possible.
 			errParam = extractFirstErrorParam(params)
-		}
+			if !opts.explicit {
+				inlineMetadata = metadataFromParams(params)
+			}
+
+			// If any of the provided params can be "upgraded" to a logMetadataProvider i.e.
+			// they themselves have a LogMetadata method that returns a map[string]string
+			// then we merge these params with the metadata.
+			for _, param := range params {
+				param, ok := param.(logMetadataProvider)
+				if !ok {
+					continue
+				}
+				providerMetadata = mergeMetadata(providerMetadata, stringMapToInterfaceMap(param.LogMetadata()))
+			}
 
-		// If any of the provided params can be "upgraded" to a logMetadataProvider i.e.
-		// they themselves have a LogMetadata method that returns a map[string]string
-		// then we merge these params with the metadata.
-		for _, param := range params {
-			param, ok := param.(logMetadataProvider)
-			if !ok {
-				continue
+			// Similarly, params which are logLabelsProviders contribute to the labels. As with metadata, the first
+			// value provided for a key wins.
+			for _, param := range params {
+				param, ok := param.(logLabelsProvider)
+				if !ok {
+					continue
+				}
+				labels = mergeLabels(labels, param.LogLabels())
+			}
+
+			// ErrorCoders have their code promoted to a label, so it can be indexed, and their params kept as
+			// structured metadata rather than only being stringified. This applies even if the error has been wrapped.
+			if code, errParams, ok := findErrorCode(errParam); ok {
+				if code != "" {
+					labels = mergeLabels(labels, map[string]string{ErrorCodeLabelKey: code})
+				}
+				if len(errParams) > 0 {
+					providerMetadata = mergeMetadata(providerMetadata, map[string]interface{}{
+						ErrorParamsMetadataKey: mergeLabels(nil, errParams),
+					})
+				}
 			}
-			metadata = mergeMetadata(metadata, stringMapToInterfaceMap(param.LogMetadata()))
 		}
 
-		if fmtOperands > 0 {
+		if fmtOperands > 0 && (formatErr == nil || formatErrorMode() != FormatErrorRaw) {
 			endIndex := len(params) - extraParamCount
 			if hasFormatOverflow {
 				endIndex = len(params)
 			}
 			nonMetaParams := params[0:endIndex]
-			msg = fmt.Sprintf(msg, nonMetaParams...)
+			msg = fmt.Sprintf(msg, formatErrorOperands(msg, nonMetaParams)...)
 		}
 	}
 
+	if opts.explicit {
+		inlineMetadata = opts.metadata
+	}
+	if formatErr != nil && formatErrorMode() == FormatErrorRaw {
+		providerMetadata = mergeMetadata(providerMetadata, map[string]interface{}{
+			FormatErrorMetadataKey: formatErr.Error(),
+		})
+	}
+
+	// Metadata is assembled from each source in increasing order of precedence,
+	// with each source overwriting values from the ones before it:
+	//
+	//   1. defaults for the event's severity (see SetSeverityDefaults)
+	//   2. the calling function (see SetCaptureCaller)
+	//   3. the state of the context (see SetAnnotateContextState)
+	//   4. values extracted from the context (see RegisterContextExtractor)
+	//   5. params stored in the context (see WithParams)
+	//   6. params which implement logMetadataProvider, and the params of an ErrorCoder
+	//   7. inline map[string]string or map[string]interface{} params, or the metadata given to EventfWithMetadata
+	//
+	// Within a single source, the first value provided for a key wins. Keys
+	// configured with SetAppendableMetadataKeys are accumulated across sources
+	// rather than overwritten.
+	appendable := appendableMetadataKeys()
+	metadata := layerMetadata(nil, metadataFromSeverity(sev), appendable)
+	metadata = layerMetadata(metadata, metadataFromCaller(), appendable)
+	metadata = layerMetadata(metadata, metadataFromContextState(ctx), appendable)
+	metadata = layerMetadata(metadata, metadataFromExtractors(ctx), appendable)
+	metadata = layerMetadata(metadata, metadataFromContext(ctx), appendable)
+	metadata = layerMetadata(metadata, providerMetadata, appendable)
+	metadata = layerMetadata(metadata, inlineMetadata, appendable)
+
+	if opts.template {
+		var missing []string
+		msg, missing = renderTemplate(msg, metadata)
+		if len(missing) > 0 {
+			metadata = mergeMetadataOverwrite(metadata, map[string]interface{}{
+				TemplateMissingMetadataKey: missing,
+			})
+		}
+	}
+
+	if sanitizeMessagesEnabled() {
+		msg = sanitize(msg)
+		sanitizeMetadata(metadata)
+	}
+
 	event := Event{
 		Context:         ctx,
-		Id:              id.String(),
-		Timestamp:       time.Now().UTC(),
+		Id:              id,
+		Timestamp:       timestamp,
+		Sequence:        seq,
 		Severity:        sev,
 		Message:         msg,
 		OriginalMessage: originalMessage,
 		Metadata:        metadata,
+		Labels:          labels,
 		Error:           errParam,
 	}
-
+	notifyBuilt(event)
 	return event
 }
 
+// reportInvalidFormat sends a Warn event describing a malformed format string to the default Logger.
+func reportInvalidFormat(ctx context.Context, msg string, nParams int) {
+	err := ValidateFormat(msg, nParams)
+	if err == nil {
+		return
+	}
+	// This event mustn't claim the severity callbacks of the one being built (see notifyBuilt)
+	if ctx.Value(notifyKey{}) != nil {
+		ctx = context.WithValue(ctx, notifyKey{}, (*pendingNotification)(nil))
+	}
+	// The format string is passed as metadata rather than interpolated, so this can't recurse
+	Log(Eventf(WarnSeverity, ctx, "Malformed log format string", err, map[string]interface{}{
+		"format": msg,
+	}))
+}
+
+// hasStructuredParams reports whether any of the params is an error, metadata map, logMetadataProvider or
+// logLabelsProvider, from which eventf extracts structured data.
+func hasStructuredParams(params []interface{}) bool {
+	for _, param := range params {
+		switch param.(type) {
+		case error, map[string]string, map[string]interface{}, logMetadataProvider, logLabelsProvider:
+			return true
+		}
+	}
+	return false
+}
+
 func extractFirstErrorParam(params []interface{}) error {
 	for _, param := range params {
 		err, ok := param.(error)
@@ -207,3 +697,57 @@ func mergeMetadata(current, new map[string]interface{}) map[string]interface{} {
 
 	return current
 }
+
+// mergeMetadataOverwrite merges the metadata, replacing existing entries
+func mergeMetadataOverwrite(current, new map[string]interface{}) map[string]interface{} {
+	if len(new) == 0 {
+		return current
+	}
+
+	if current == nil {
+		current = make(map[string]interface{}, len(new))
+	}
+
+	for k, v := range new {
+		current[k] = v
+	}
+
+	return current
+}
+
+// layerMetadata merges the metadata, replacing existing entries unless their key is appendable, in which case the
+// values are combined with appendMetadataValues.
+func layerMetadata(current, new map[string]interface{}, appendable map[string]struct{}) map[string]interface{} {
+	if len(appendable) == 0 {
+		return mergeMetadataOverwrite(current, new)
+	}
+
+	for k, v := range new {
+		if _, ok := appendable[k]; ok {
+			if existing, ok := current[k]; ok {
+				v = appendMetadataValues(existing, v)
+			}
+		}
+		current = mergeMetadataOverwrite(current, map[string]interface{}{k: v})
+	}
+	return current
+}
+
+// mergeLabels merges the labels but preserves existing entries
+func mergeLabels(current, new map[string]string) map[string]string {
+	if len(new) == 0 {
+		return current
+	}
+
+	if current == nil {
+		current = map[string]string{}
+	}
+
+	for k, v := range new {
+		if _, ok := current[k]; !ok {
+			current[k] = v
+		}
+	}
+
+	return current
+}
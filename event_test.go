@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEventfFormatsParams(t *testing.T) {
@@ -22,6 +24,54 @@ func TestEventfNilContext(t *testing.T) {
 	}
 }
 
+func TestNewEvent(t *testing.T) {
+	fixed := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return fixed
+	})
+	defer ResetClock()
+	ctx := WithParams(context.Background(), map[string]string{"request_id": "r1"})
+
+	e := NewEvent(WarnSeverity, ctx, "100% done %s")
+	assert.NotEmpty(t, e.Id)
+	assert.Equal(t, fixed, e.Timestamp)
+	assert.NotZero(t, e.Sequence)
+	assert.Equal(t, WarnSeverity, e.Severity)
+	assert.Equal(t, "100% done %s", e.Message)
+	assert.Equal(t, "100% done %s", e.OriginalMessage)
+	assert.Equal(t, ctx, e.Context)
+	assert.Nil(t, e.Metadata)
+	assert.Nil(t, e.Labels)
+	assert.Nil(t, e.Error)
+
+	other := NewEvent(WarnSeverity, nil, "other")
+	assert.NotNil(t, other.Context)
+	assert.NotEqual(t, e.Id, other.Id)
+	assert.Greater(t, other.Sequence, e.Sequence)
+}
+
+func TestEventfIDGeneratorFailure(t *testing.T) {
+	SetIDGenerator(func() (string, error) {
+		return "", errors.New("no entropy")
+	})
+	defer ResetIDGenerator()
+
+	e := Eventf(WarnSeverity, context.Background(), "foo: %s", "bar", map[string]interface{}{
+		"meta": "data",
+	})
+	assert.NotEmpty(t, e.Id)
+	assert.False(t, e.Timestamp.IsZero())
+	assert.Equal(t, WarnSeverity, e.Severity)
+	assert.Equal(t, "foo: bar", e.Message)
+	assert.Equal(t, "foo: %s", e.OriginalMessage)
+	assert.Equal(t, map[string]interface{}{
+		"meta": "data",
+	}, e.Metadata)
+
+	other := Eventf(WarnSeverity, context.Background(), "foo")
+	assert.NotEqual(t, e.Id, other.Id)
+}
+
 func TestOriginalMessagePreserved(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -188,6 +238,38 @@ func TestEventMetadata(t *testing.T) {
 			expectedMessage: "Foo bar",
 			expectedError:   nil,
 		},
+		{
+			desc:            "Literal percent sign with error",
+			message:         "Disk at 50%, failing",
+			params:          []interface{}{assert.AnError},
+			expected:        map[string]interface{}(nil),
+			expectedMessage: "Disk at 50%, failing",
+			expectedError:   assert.AnError,
+		},
+		{
+			desc:    "Literal percent sign with metadata",
+			message: "Disk at 50%, failing",
+			params: []interface{}{map[string]interface{}{
+				"disk": "sda",
+			}},
+			expected: map[string]interface{}{
+				"disk": "sda",
+			},
+			expectedMessage: "Disk at 50%, failing",
+			expectedError:   nil,
+		},
+		{
+			desc:    "Literal percent sign with operand and metadata",
+			message: "%s at 50%, failing",
+			params: []interface{}{"sda", map[string]interface{}{
+				"disk": "sda",
+			}},
+			expected: map[string]interface{}{
+				"disk": "sda",
+			},
+			expectedMessage: "sda at 50%!,(MISSING) failing",
+			expectedError:   nil,
+		},
 		{
 			desc:            "Invalid: too many format params",
 			message:         "Foo %s %s",
@@ -239,12 +321,134 @@ func TestEventMetadata(t *testing.T) {
 	}
 }
 
+func TestOnFormatError(t *testing.T) {
+	defer SetOnFormatError(FormatErrorKeep)
+	testCases := []struct {
+		message         string
+		params          []interface{}
+		expected        map[string]interface{}
+		expectedMessage string
+		expectedError   error
+	}{
+		{
+			message:         "Foo %s %s",
+			params:          []interface{}{"bar"},
+			expectedMessage: "Foo bar %!s(MISSING)",
+		},
+		{
+			message: "Foo %s %s %s",
+			params: []interface{}{"bar", map[string]interface{}{
+				"meta": "data",
+			}},
+			expected: map[string]interface{}{
+				"meta": "data",
+			},
+			expectedMessage: "Foo bar map[meta:data] %!s(MISSING)",
+		},
+		{
+			message:         "Foo %s %s %s",
+			params:          []interface{}{"bar", assert.AnError},
+			expectedMessage: "Foo bar assert.AnError general error for testing %!s(MISSING)",
+			expectedError:   assert.AnError,
+		},
+		{
+			message: "Foo %s %s %s %s",
+			params: []interface{}{"bar", assert.AnError, map[string]interface{}{
+				"meta": "data",
+			}},
+			expected: map[string]interface{}{
+				"meta": "data",
+			},
+			expectedMessage: "Foo bar assert.AnError general error for testing map[meta:data] %!s(MISSING)",
+			expectedError:   assert.AnError,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.message, func(t *testing.T) {
+			SetOnFormatError(FormatErrorKeep)
+			e := Eventf(ErrorSeverity, nil, tC.message, tC.params...)
+			assert.EqualValues(t, tC.expected, e.Metadata)
+			assert.Equal(t, tC.expectedMessage, e.Message)
+			assert.Equal(t, tC.expectedError, e.Error)
+
+			SetOnFormatError(FormatErrorRaw)
+			e = Eventf(ErrorSeverity, nil, tC.message, tC.params...)
+			expected := mergeMetadata(map[string]interface{}{
+				FormatErrorMetadataKey: ValidateFormat(tC.message, len(tC.params)).Error(),
+			}, tC.expected)
+			assert.Equal(t, expected, e.Metadata)
+			assert.Equal(t, tC.message, e.Message)
+			assert.Equal(t, tC.message, e.OriginalMessage)
+			assert.Equal(t, tC.expectedError, e.Error)
+		})
+	}
+
+	// Well-formed events are unaffected
+	e := Eventf(InfoSeverity, nil, "Foo %s", "bar", map[string]string{"meta": "data"})
+	assert.Equal(t, "Foo bar", e.Message)
+	assert.Equal(t, map[string]interface{}{"meta": "data"}, e.Metadata)
+
+	// EventfWithMetadata has no trailing metadata to account for, so it falls back on any mismatch
+	e = EventfWithMetadata(InfoSeverity, nil, nil, "Foo %s %s", "bar")
+	assert.Equal(t, "Foo %s %s", e.Message)
+	assert.Contains(t, e.Metadata, FormatErrorMetadataKey)
+}
+
 type testLogMetadataProvider map[string]string
 
 func (p testLogMetadataProvider) LogMetadata() map[string]string {
 	return p
 }
 
+func TestEventfWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	operand := map[string]interface{}{"colour": "red"}
+
+	// Eventf takes a trailing map to be metadata if the format string doesn't use it...
+	e := Eventf(InfoSeverity, ctx, "Widget loaded", operand)
+	assert.Equal(t, "Widget loaded", e.Message)
+	assert.Equal(t, operand, e.Metadata)
+
+	// ...but EventfWithMetadata never does
+	e = EventfWithMetadata(InfoSeverity, ctx, nil, "Widget loaded %v", operand)
+	assert.Equal(t, "Widget loaded map[colour:red]", e.Message)
+	assert.Empty(t, e.Metadata)
+
+	e = EventfWithMetadata(InfoSeverity, ctx, map[string]interface{}{"widget_id": "w1"},
+		"Widget %s loaded with %v", "w1", map[string]string{"colour": "red"})
+	assert.Equal(t, "Widget w1 loaded with map[colour:red]", e.Message)
+	assert.Equal(t, "Widget %s loaded with %v", e.OriginalMessage)
+	assert.Equal(t, map[string]interface{}{"widget_id": "w1"}, e.Metadata)
+
+	// Mismatched operands are formatted rather than guessed at
+	e = EventfWithMetadata(InfoSeverity, ctx, nil, "Widget loaded", operand)
+	assert.Equal(t, "Widget loaded%!(EXTRA map[string]interface {}=map[colour:red])", e.Message)
+	assert.Empty(t, e.Metadata)
+
+	e = EventfWithMetadata(ErrorSeverity, ctx, map[string]interface{}{"widget_id": "w1"}, "Failed to load widget")
+	assert.Equal(t, "Failed to load widget", e.Message)
+	assert.Equal(t, map[string]interface{}{"widget_id": "w1"}, e.Metadata)
+
+	err := errors.New("boom")
+	e = EventfWithMetadata(ErrorSeverity, ctx, nil, "Failed to load widget: %v", err)
+	assert.Equal(t, err, e.Error)
+}
+
+func TestEventfWithMetadataStrictMode(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	assert.Panics(t, func() {
+		EventfWithMetadata(InfoSeverity, context.Background(), nil, "Widget loaded", "w1")
+	})
+	assert.Panics(t, func() {
+		EventfWithMetadata(InfoSeverity, context.Background(), nil, "Widget %s loaded")
+	})
+	assert.NotPanics(t, func() {
+		EventfWithMetadata(InfoSeverity, context.Background(), nil, "Widget %s loaded", "w1")
+	})
+}
+
 func TestEventfLogMetadataProvider(t *testing.T) {
 	param := testLogMetadataProvider{
 		"foo": "bar",
@@ -257,6 +461,209 @@ func TestEventfLogMetadataProvider(t *testing.T) {
 	assert.EqualValues(t, expected, e.Metadata)
 }
 
+type testLogLabelsProvider map[string]string
+
+func (p testLogLabelsProvider) LogLabels() map[string]string {
+	return p
+}
+
+type testLogMetadataAndLabelsProvider struct {
+	metadata map[string]string
+	labels   map[string]string
+}
+
+func (p testLogMetadataAndLabelsProvider) LogMetadata() map[string]string {
+	return p.metadata
+}
+
+func (p testLogMetadataAndLabelsProvider) LogLabels() map[string]string {
+	return p.labels
+}
+
+func TestEventfLogLabelsProvider(t *testing.T) {
+	param := testLogLabelsProvider{
+		"foo": "bar",
+	}
+
+	e := Eventf(CriticalSeverity, nil, "foo: %v", param)
+	expected := map[string]string{
+		"foo": "bar",
+	}
+	assert.EqualValues(t, expected, e.Labels)
+	assert.Nil(t, e.Metadata)
+}
+
+func TestEventfLogLabelsProviderPrecedence(t *testing.T) {
+	e := Eventf(CriticalSeverity, nil, "foo", testLogLabelsProvider{
+		"foo": "first",
+	}, testLogLabelsProvider{
+		"foo": "second",
+		"bar": "second",
+	})
+	expected := map[string]string{
+		"foo": "first",
+		"bar": "second",
+	}
+	assert.EqualValues(t, expected, e.Labels)
+}
+
+func TestEventfLogMetadataAndLabelsProvider(t *testing.T) {
+	param := testLogMetadataAndLabelsProvider{
+		metadata: map[string]string{
+			"meta": "data",
+		},
+		labels: map[string]string{
+			"label": "value",
+		},
+	}
+
+	e := Eventf(CriticalSeverity, nil, "foo", param)
+	assert.EqualValues(t, map[string]interface{}{
+		"meta": "data",
+	}, e.Metadata)
+	assert.EqualValues(t, map[string]string{
+		"label": "value",
+	}, e.Labels)
+}
+
+func TestEventFields(t *testing.T) {
+	ts := time.Now()
+	event := Event{
+		Id:        "test",
+		Timestamp: ts,
+		Severity:  ErrorSeverity,
+		Message:   "foo",
+		Metadata: map[string]interface{}{
+			"number":    42,
+			"message":   "collides with message",
+			"label.foo": "collides with label",
+		},
+		Labels: map[string]string{
+			"foo": "bar",
+		},
+		Error: assert.AnError,
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"id":        "test",
+		"timestamp": ts,
+		"severity":  "ERROR",
+		"message":   "foo",
+		"error":     assert.AnError,
+		"number":    42,
+		"label.foo": "bar",
+	}, event.Fields())
+}
+
+func TestEventStringNestedMetadata(t *testing.T) {
+	e := Event{
+		Timestamp: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Severity:  InfoSeverity,
+		Message:   "foo",
+		Metadata: map[string]interface{}{
+			"count": 1,
+			"ids":   []string{"a", "b"},
+			"user": map[string]interface{}{
+				"id":    42,
+				"roles": []interface{}{"admin"},
+			},
+			"deep": map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": map[string]interface{}{
+				"d": map[string]interface{}{"e": map[string]interface{}{"f": "too deep"}},
+			}}}},
+		},
+		Id: "id",
+	}
+
+	assert.Equal(t, `[2020-01-02 03:04:05+0000 (UTC)] INFO foo (error= metadata=map[count:1 `+
+		`deep:{"a":{"b":{"c":{"d":{"e":"..."}}}}} ids:["a","b"] user:{"id":42,"roles":["admin"]}] labels=map[] id=id)`,
+		e.String())
+}
+
+func TestEventWithMetadataAndLabel(t *testing.T) {
+	var e Event
+	withMeta := e.WithMetadata("count", 1)
+	withLabel := withMeta.WithLabel("service", "widgets")
+	assert.Nil(t, e.Metadata)
+	assert.Nil(t, e.Labels)
+	assert.Equal(t, map[string]interface{}{"count": 1}, withLabel.Metadata)
+	assert.Equal(t, map[string]string{"service": "widgets"}, withLabel.Labels)
+
+	overwritten := withLabel.WithMetadata("count", 2).WithLabel("service", "gadgets")
+	assert.Equal(t, map[string]interface{}{"count": 2}, overwritten.Metadata)
+	assert.Equal(t, map[string]string{"service": "gadgets"}, overwritten.Labels)
+	assert.Equal(t, map[string]interface{}{"count": 1}, withLabel.Metadata)
+	assert.Equal(t, map[string]string{"service": "widgets"}, withLabel.Labels)
+}
+
+func TestEventfMetadataPrecedence(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		params   []interface{}
+		expected map[string]interface{}
+	}{
+		{
+			desc: "provider only",
+			params: []interface{}{
+				testLogMetadataProvider{"foo": "provider"},
+			},
+			expected: map[string]interface{}{"foo": "provider"},
+		},
+		{
+			desc: "inline only",
+			params: []interface{}{
+				map[string]interface{}{"foo": "inline"},
+			},
+			expected: map[string]interface{}{"foo": "inline"},
+		},
+		{
+			desc: "inline overwrites provider",
+			params: []interface{}{
+				testLogMetadataProvider{"foo": "provider"},
+				map[string]interface{}{"foo": "inline"},
+			},
+			expected: map[string]interface{}{"foo": "inline"},
+		},
+		{
+			desc: "inline overwrites provider regardless of order",
+			params: []interface{}{
+				map[string]string{"foo": "inline"},
+				testLogMetadataProvider{"foo": "provider"},
+			},
+			expected: map[string]interface{}{"foo": "inline"},
+		},
+		{
+			desc: "first provider wins",
+			params: []interface{}{
+				testLogMetadataProvider{"foo": "first"},
+				testLogMetadataProvider{"foo": "second", "bar": "second"},
+			},
+			expected: map[string]interface{}{"foo": "first", "bar": "second"},
+		},
+		{
+			desc: "first inline map wins",
+			params: []interface{}{
+				map[string]interface{}{"foo": "first"},
+				map[string]string{"foo": "second", "bar": "second"},
+			},
+			expected: map[string]interface{}{"foo": "first", "bar": "second"},
+		},
+		{
+			desc: "disjoint keys are combined",
+			params: []interface{}{
+				testLogMetadataProvider{"provider": "provider"},
+				map[string]interface{}{"inline": "inline"},
+			},
+			expected: map[string]interface{}{"provider": "provider", "inline": "inline"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			e := Eventf(InfoSeverity, nil, "foo", tC.params...)
+			assert.EqualValues(t, tC.expected, e.Metadata)
+		})
+	}
+}
+
 func TestSerializeDeserialize(t *testing.T) {
 	event := Event{
 		Context:         context.Background(),
@@ -277,6 +684,11 @@ func TestSerializeDeserialize(t *testing.T) {
 	out, err := json.Marshal(&event)
 	assert.NoError(t, err)
 
+	var raw map[string]interface{}
+	err = json.Unmarshal(out, &raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "ERROR", raw["severity"])
+
 	var undo Event
 	err = json.Unmarshal(out, &undo)
 	assert.NoError(t, err)
@@ -287,9 +699,83 @@ func TestSerializeDeserialize(t *testing.T) {
 	assert.Equal(t, event.Metadata, undo.Metadata)
 	assert.Equal(t, event.Labels, undo.Labels)
 
-	// Note: go error types will not serialize by default, so we do not expect
-	// any data here.
-	assert.Equal(t, map[string]interface{}{}, undo.Error)
+	// Go errors are encoded with their message
+	assert.Equal(t, map[string]interface{}{
+		"message": "an error",
+	}, undo.Error)
+}
+
+func TestSerializeDeserializeTerrorsError(t *testing.T) {
+	event := Event{
+		Id:        "test",
+		Timestamp: time.Now(),
+		Severity:  ErrorSeverity,
+		Message:   "foo",
+		Error: &terrorsError{
+			Code:    "bad_request",
+			Message: "missing widget",
+			Params:  map[string]string{"widget_id": "w1"},
+		},
+	}
+	out, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	var undo Event
+	err = json.Unmarshal(out, &undo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"code":    "bad_request",
+		"message": "bad_request: missing widget",
+		"params":  map[string]interface{}{"widget_id": "w1"},
+	}, undo.Error)
+}
+
+// joinedError behaves like an error created by errors.Join.
+type joinedError []error
+
+func (e joinedError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e joinedError) Unwrap() []error {
+	return e
+}
+
+func TestSerializeDeserializeJoinedError(t *testing.T) {
+	event := Event{
+		Id:        "test",
+		Timestamp: time.Now(),
+		Severity:  ErrorSeverity,
+		Message:   "foo",
+		Error: joinedError{
+			&terrorsError{Code: "bad_request", Message: "missing widget"},
+			errors.New("simple"),
+		},
+	}
+	out, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	var undo Event
+	err = json.Unmarshal(out, &undo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "bad_request: missing widget\nsimple",
+		"errors": []interface{}{
+			map[string]interface{}{
+				"code":    "bad_request",
+				"message": "bad_request: missing widget",
+			},
+			map[string]interface{}{
+				"message": "simple",
+			},
+		},
+	}, undo.Error)
 }
 
 func TestSerializeDeserializeError(t *testing.T) {
@@ -333,6 +819,94 @@ func TestSerializeDeserializeError(t *testing.T) {
 	}, undo.Error)
 }
 
+// unmarshallableError is an error whose MarshalJSON always fails.
+type unmarshallableError struct{}
+
+func (unmarshallableError) Error() string {
+	return "unmarshallable"
+}
+
+func (unmarshallableError) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("can't marshal")
+}
+
+func TestSerializeUnserializableValues(t *testing.T) {
+	e := Eventf(ErrorSeverity, context.Background(), "foo", unmarshallableError{}, map[string]interface{}{
+		"ch":    make(chan int),
+		"fn":    func() {},
+		"ok":    "fine",
+		"inner": map[string]interface{}{"ch": make(chan string), "n": 1},
+		"list":  []interface{}{1, make(chan int)},
+	})
+
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+	decoded := Event{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "foo", decoded.Message)
+	assert.Equal(t, "<unserializable: slog.unmarshallableError>", decoded.Error)
+	assert.Equal(t, map[string]interface{}{
+		"ch":    "<unserializable: chan int>",
+		"fn":    "<unserializable: func()>",
+		"ok":    "fine",
+		"inner": map[string]interface{}{"ch": "<unserializable: chan string>", "n": float64(1)},
+		"list":  []interface{}{float64(1), "<unserializable: chan int>"},
+	}, decoded.Metadata)
+
+	// The event itself is unchanged
+	assert.IsType(t, make(chan int), e.Metadata["ch"])
+}
+
+func TestSeverityUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Severity
+	}{
+		{`"CRITICAL"`, CriticalSeverity},
+		{`"error"`, ErrorSeverity},
+		{`"Warn"`, WarnSeverity},
+		{`"INFO"`, InfoSeverity},
+		{`"DEBUG"`, DebugSeverity},
+		{`"TRACE"`, TraceSeverity},
+		{`5`, ErrorSeverity},
+		{`1`, TraceSeverity},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.input, func(t *testing.T) {
+			var sev Severity
+			assert.NoError(t, json.Unmarshal([]byte(tC.input), &sev))
+			assert.Equal(t, tC.expected, sev)
+		})
+	}
+
+	var sev Severity
+	assert.Error(t, json.Unmarshal([]byte(`"LOUD"`), &sev))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &sev))
+}
+
+func TestSetSeverityNames(t *testing.T) {
+	SetSeverityNames(map[Severity]string{
+		WarnSeverity: "WARNING",
+		InfoSeverity: "info",
+	})
+	defer ResetSeverityNames()
+
+	assert.Equal(t, "WARNING", WarnSeverity.String())
+	assert.Equal(t, "ERROR", ErrorSeverity.String())
+	e := Eventf(InfoSeverity, context.Background(), "foo")
+	assert.Contains(t, e.String(), " info foo ")
+
+	sev, err := ParseSeverity("warning")
+	assert.NoError(t, err)
+	assert.Equal(t, WarnSeverity, sev)
+	sev, err = ParseSeverity("WARN")
+	assert.NoError(t, err)
+	assert.Equal(t, WarnSeverity, sev)
+
+	ResetSeverityNames()
+	assert.Equal(t, "WARN", WarnSeverity.String())
+}
+
 func BenchmarkLogMetadataInterface(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Eventf(ErrorSeverity, nil, "foo", map[string]interface{}{
@@ -2,6 +2,8 @@ package slog
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 )
 
 // EventSet is a time-sortable collection of logging events.
@@ -30,3 +32,44 @@ func (es EventSet) String() string {
 	}
 	return buf.String()
 }
+
+// Filter returns the events for which f returns true.
+func (es EventSet) Filter(f func(Event) bool) EventSet {
+	result := EventSet{}
+	for _, e := range es {
+		if f(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// WithSeverity returns the events with the given severity.
+func (es EventSet) WithSeverity(sev Severity) EventSet {
+	return es.Filter(func(e Event) bool {
+		return e.Severity == sev
+	})
+}
+
+// Containing returns the events whose message contains substr.
+func (es EventSet) Containing(substr string) EventSet {
+	return es.Filter(func(e Event) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// WithMetadata returns the events with a metadata entry for key whose value, formatted as a string, is value.
+func (es EventSet) WithMetadata(key, value string) EventSet {
+	return es.Filter(func(e Event) bool {
+		v, ok := e.Metadata[key]
+		return ok && fmt.Sprint(v) == value
+	})
+}
+
+// Last returns the last event in the set, if there is one.
+func (es EventSet) Last() (Event, bool) {
+	if len(es) == 0 {
+		return Event{}, false
+	}
+	return es[len(es)-1], true
+}
@@ -0,0 +1,46 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSetQueries(t *testing.T) {
+	ctx := context.Background()
+	es := EventSet{
+		Eventf(InfoSeverity, ctx, "request started"),
+		Eventf(ErrorSeverity, ctx, "request timeout", map[string]interface{}{
+			"attempt": 1,
+		}),
+		Eventf(ErrorSeverity, ctx, "request failed", map[string]string{
+			"attempt": "2",
+		}),
+		Eventf(ErrorSeverity, ctx, "request timeout", map[string]interface{}{
+			"attempt": 3,
+		}),
+	}
+
+	assert.Len(t, es.WithSeverity(ErrorSeverity), 3)
+	assert.Empty(t, es.WithSeverity(CriticalSeverity))
+	assert.Len(t, es.Containing("timeout"), 2)
+	assert.Len(t, es.WithSeverity(ErrorSeverity).Containing("timeout"), 2)
+
+	attempt2 := es.WithMetadata("attempt", "2")
+	require.Len(t, attempt2, 1)
+	assert.Equal(t, "request failed", attempt2[0].Message)
+	assert.Len(t, es.WithMetadata("attempt", "1"), 1)
+	assert.Empty(t, es.WithMetadata("missing", ""))
+
+	last, ok := es.Containing("timeout").Last()
+	require.True(t, ok)
+	assert.Equal(t, 3, last.Metadata["attempt"])
+
+	_, ok = es.Containing("nothing").Last()
+	assert.False(t, ok)
+
+	// The receiver should be unchanged
+	assert.Len(t, es, 4)
+}
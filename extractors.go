@@ -0,0 +1,69 @@
+package slog
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type contextExtractor struct {
+	name string
+	fn   func(context.Context) (string, bool)
+}
+
+var (
+	contextExtractors  []contextExtractor
+	contextExtractorsM sync.RWMutex
+)
+
+// RegisterContextExtractor registers fn to be run against the context of every event constructed by Eventf, adding the
+// value it returns to the event's metadata under name. This allows request-scoped values stored under other packages'
+// context keys, such as a tenant or user ID, to be logged without being copied into the context with WithParams.
+// Extractors which return false are skipped.
+//
+// Registering another extractor with the same name replaces it, and registering a nil fn removes it. Values from
+// extractors take precedence over the state of the context, but not over params stored with WithParams.
+func RegisterContextExtractor(name string, fn func(context.Context) (string, bool)) {
+	contextExtractorsM.Lock()
+	defer contextExtractorsM.Unlock()
+
+	extractors := make([]contextExtractor, 0, len(contextExtractors)+1)
+	for _, e := range contextExtractors {
+		if e.name != name {
+			extractors = append(extractors, e)
+		}
+	}
+	if fn != nil {
+		extractors = append(extractors, contextExtractor{
+			name: name,
+			fn:   fn,
+		})
+		sort.Slice(extractors, func(i, j int) bool {
+			return extractors[i].name < extractors[j].name
+		})
+	}
+	contextExtractors = extractors
+}
+
+// metadataFromExtractors returns the values of the registered context extractors for ctx.
+func metadataFromExtractors(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	contextExtractorsM.RLock()
+	extractors := contextExtractors
+	contextExtractorsM.RUnlock()
+
+	var result map[string]interface{}
+	for _, e := range extractors {
+		v, ok := e.fn(ctx)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]interface{}, len(extractors))
+		}
+		result[e.name] = v
+	}
+	return result
+}
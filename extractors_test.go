@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantIDKey struct{}
+
+type userIDKey struct{}
+
+func TestContextExtractors(t *testing.T) {
+	RegisterContextExtractor("tenant_id", func(ctx context.Context) (string, bool) {
+		id, ok := ctx.Value(tenantIDKey{}).(string)
+		return id, ok
+	})
+	defer RegisterContextExtractor("tenant_id", nil)
+	RegisterContextExtractor("user_id", func(ctx context.Context) (string, bool) {
+		id, ok := ctx.Value(userIDKey{}).(string)
+		return id, ok && id != ""
+	})
+	defer RegisterContextExtractor("user_id", nil)
+
+	ctx := context.Background()
+	assert.Empty(t, Eventf(InfoSeverity, ctx, "Neither").Metadata)
+
+	ctx = context.WithValue(ctx, tenantIDKey{}, "tenant1")
+	ctx = context.WithValue(ctx, userIDKey{}, "")
+	assert.Equal(t, map[string]interface{}{
+		"tenant_id": "tenant1",
+	}, Eventf(InfoSeverity, ctx, "Tenant only").Metadata)
+
+	ctx = context.WithValue(ctx, userIDKey{}, "user1")
+	assert.Equal(t, map[string]interface{}{
+		"tenant_id": "tenant1",
+		"user_id":   "user1",
+	}, Eventf(InfoSeverity, ctx, "Both").Metadata)
+
+	// Params stored in the context and inline metadata take precedence
+	ctx = WithParams(ctx, map[string]string{"tenant_id": "from_params"})
+	e := Eventf(InfoSeverity, ctx, "Overridden", map[string]string{
+		"user_id": "inline",
+	})
+	assert.Equal(t, "from_params", e.Metadata["tenant_id"])
+	assert.Equal(t, "inline", e.Metadata["user_id"])
+
+	// Re-registering a name replaces its extractor
+	RegisterContextExtractor("user_id", func(context.Context) (string, bool) {
+		return "replaced", true
+	})
+	assert.Equal(t, "replaced", Eventf(InfoSeverity, ctx, "Replaced").Metadata["user_id"])
+
+	RegisterContextExtractor("user_id", nil)
+	assert.NotContains(t, Eventf(InfoSeverity, ctx, "Removed").Metadata, "user_id")
+}
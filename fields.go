@@ -0,0 +1,64 @@
+package slog
+
+// A FieldsLogger adds a static set of labels and metadata to every event before forwarding it to another Logger. It is
+// intended for process-wide constants such as the service name or version.
+type FieldsLogger struct {
+	next     Logger
+	labels   map[string]string
+	metadata map[string]interface{}
+}
+
+// NewFieldsLogger creates a FieldsLogger which adds the given labels and metadata to each event. Values already present
+// on an event take precedence over the static ones.
+func NewFieldsLogger(next Logger, labels map[string]string, metadata map[string]interface{}) *FieldsLogger {
+	return &FieldsLogger{
+		next:     next,
+		labels:   mergeLabels(nil, labels),
+		metadata: mergeMetadata(nil, metadata),
+	}
+}
+
+// Log the events to the underlying logger with the static fields added.
+func (l *FieldsLogger) Log(evs ...Event) {
+	decorated := make([]Event, len(evs))
+	for i, e := range evs {
+		// Copy the event's maps rather than writing to them, as they may be shared with other loggers
+		if len(l.labels) > 0 {
+			e.Labels = mergeLabels(mergeLabels(nil, e.Labels), l.labels)
+		}
+		if len(l.metadata) > 0 {
+			e.Metadata = mergeMetadata(mergeMetadata(nil, e.Metadata), l.metadata)
+		}
+		decorated[i] = e
+	}
+	l.next.Log(decorated...)
+}
+
+// Flush the underlying logger.
+func (l *FieldsLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// WorkerLabelKey is the label key under which a WorkerLogger records its worker's name.
+const WorkerLabelKey = "worker"
+
+// A WorkerLogger labels every event with the name of the worker which logged it, so that events from a pool of
+// workers can be told apart. Each worker should create its own WorkerLogger when it starts.
+type WorkerLogger struct {
+	*FieldsLogger
+	name string
+}
+
+// NewWorkerLogger creates a WorkerLogger which labels events with the given worker name before forwarding them to
+// next. A worker label already present on an event takes precedence.
+func NewWorkerLogger(next Logger, name string) *WorkerLogger {
+	return &WorkerLogger{
+		FieldsLogger: NewFieldsLogger(next, map[string]string{WorkerLabelKey: name}, nil),
+		name:         name,
+	}
+}
+
+// Name returns the name of the worker.
+func (l *WorkerLogger) Name() string {
+	return l.name
+}
@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	labels := map[string]string{
+		"service": "payments",
+	}
+	metadata := map[string]interface{}{
+		"version": "1.2.3",
+		"region":  "eu",
+	}
+	logger := NewFieldsLogger(next, labels, metadata)
+
+	e := Eventf(InfoSeverity, context.Background(), "foo", map[string]interface{}{
+		"region": "us",
+	})
+	logger.Log(e)
+
+	events := next.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, map[string]string{
+		"service": "payments",
+	}, events[0].Labels)
+	assert.Equal(t, map[string]interface{}{
+		"version": "1.2.3",
+		"region":  "us",
+	}, events[0].Metadata)
+
+	// Neither the static fields nor the original event should have been modified
+	assert.Equal(t, map[string]interface{}{
+		"version": "1.2.3",
+		"region":  "eu",
+	}, metadata)
+	assert.Equal(t, map[string]interface{}{
+		"region": "us",
+	}, e.Metadata)
+	assert.Nil(t, e.Labels)
+}
+
+func TestWorkerLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	first, second := NewWorkerLogger(next, "worker-1"), NewWorkerLogger(next, "worker-2")
+
+	first.Log(Eventf(InfoSeverity, context.Background(), "one"))
+	second.Log(Eventf(InfoSeverity, context.Background(), "two"))
+
+	events := next.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, map[string]string{WorkerLabelKey: "worker-1"}, events[0].Labels)
+	assert.Equal(t, map[string]string{WorkerLabelKey: "worker-2"}, events[1].Labels)
+	assert.Equal(t, "worker-1", first.Name())
+}
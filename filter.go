@@ -0,0 +1,213 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+type minSeverityKey struct{}
+
+// WithMinSeverity returns a copy of the parent context which overrides the minimum severity applied by a
+// LevelFilterLogger to events logged with it. This can be used to enable verbose logging for a single request without
+// changing the global threshold.
+func WithMinSeverity(ctx context.Context, sev Severity) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, minSeverityKey{}, sev)
+}
+
+// MinSeverity returns the minimum severity override stored in the context, if any.
+func MinSeverity(ctx context.Context) (Severity, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	sev, ok := ctx.Value(minSeverityKey{}).(Severity)
+	return sev, ok
+}
+
+// A LevelFilterLogger forwards only those events which meet a minimum severity to another Logger.
+//
+// If an event's context carries an override set by WithMinSeverity, the override takes precedence over the logger's
+// own minimum: it can both lower the threshold (to let more verbose events through) and raise it.
+type LevelFilterLogger struct {
+	next Logger
+	// min is the minimum Severity, accessed atomically so it can be changed while in use.
+	min int32
+}
+
+// NewLevelFilterLogger creates a LevelFilterLogger which forwards events of at least the given severity to next.
+func NewLevelFilterLogger(next Logger, min Severity) *LevelFilterLogger {
+	return &LevelFilterLogger{
+		next: next,
+		min:  int32(min),
+	}
+}
+
+// Log forwards the events which meet the minimum severity.
+func (l *LevelFilterLogger) Log(evs ...Event) {
+	filtered := make([]Event, 0, len(evs))
+	for _, e := range evs {
+		if l.enabled(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) > 0 {
+		l.next.Log(filtered...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *LevelFilterLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// Enabled reports whether events of the given severity meet the minimum severity, and would be logged by the
+// underlying logger. It does not account for overrides set with WithMinSeverity.
+func (l *LevelFilterLogger) Enabled(sev Severity) bool {
+	return sev >= l.MinSeverity() && enabled(l.next, sev)
+}
+
+// MinSeverity returns the minimum severity of events which are forwarded.
+func (l *LevelFilterLogger) MinSeverity() Severity {
+	return Severity(atomic.LoadInt32(&l.min))
+}
+
+// SetMinSeverity changes the minimum severity of events which are forwarded. It is safe to call while the logger is
+// in use.
+func (l *LevelFilterLogger) SetMinSeverity(sev Severity) {
+	atomic.StoreInt32(&l.min, int32(sev))
+}
+
+func (l *LevelFilterLogger) enabled(e Event) bool {
+	min := l.MinSeverity()
+	if override, ok := MinSeverity(e.Context); ok {
+		min = override
+	}
+	return e.Severity >= min
+}
+
+// SelectorWildcard is a selector value which matches any value, provided the label is present.
+const SelectorWildcard = "*"
+
+// A SelectorLogger forwards only those events whose labels match a selector to another Logger.
+type SelectorLogger struct {
+	next     Logger
+	selector map[string]string
+}
+
+// NewSelectorLogger creates a SelectorLogger which forwards events to next if, for every key in the selector, the
+// event has a label with that key and the same value. A selector value of SelectorWildcard only requires that the
+// label be present.
+func NewSelectorLogger(next Logger, selector map[string]string) *SelectorLogger {
+	return &SelectorLogger{
+		next:     next,
+		selector: mergeLabels(nil, selector),
+	}
+}
+
+// Log forwards the events which match the selector.
+func (l *SelectorLogger) Log(evs ...Event) {
+	filtered := make([]Event, 0, len(evs))
+	for _, e := range evs {
+		if l.matches(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) > 0 {
+		l.next.Log(filtered...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *SelectorLogger) Flush() error {
+	return l.next.Flush()
+}
+
+func (l *SelectorLogger) matches(e Event) bool {
+	for k, want := range l.selector {
+		got, ok := e.Labels[k]
+		if !ok || (want != SelectorWildcard && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// A FilterRule sets the minimum severity of events with a given label or metadata entry. A rule with an empty Key
+// matches every event, so can be used as the default.
+type FilterRule struct {
+	// Key is the label or metadata key to match.
+	Key string
+	// Value is the value to match, or SelectorWildcard to match any value, provided the key is present. Metadata values
+	// which aren't strings are compared in their fmt.Sprint form.
+	Value string
+	// MinSeverity is the minimum severity of events which match the rule.
+	MinSeverity Severity
+}
+
+// matches reports whether the event has a label or metadata entry which satisfies the rule.
+func (r FilterRule) matches(e Event) bool {
+	if r.Key == "" {
+		return true
+	}
+	if v, ok := e.Labels[r.Key]; ok && (r.Value == SelectorWildcard || v == r.Value) {
+		return true
+	}
+	v, ok := e.Metadata[r.Key]
+	if !ok || r.Value == SelectorWildcard {
+		return ok
+	}
+	if s, isString := v.(string); isString {
+		return s == r.Value
+	}
+	return fmt.Sprint(v) == r.Value
+}
+
+// A RuleFilterLogger forwards events to another Logger according to the minimum severity set by the first of its
+// rules which matches them. This allows verbosity to be controlled by attributes of events: for example, logging
+// everything labelled feature=new_checkout at Debug, and everything else at Info.
+//
+// Rules are evaluated in order, and only the first match applies, so more specific rules should come before more
+// general ones. Events which match no rule are forwarded; a final rule with an empty Key sets the default.
+type RuleFilterLogger struct {
+	next  Logger
+	rules []FilterRule
+}
+
+// NewRuleFilterLogger creates a RuleFilterLogger which filters events by the given rules before forwarding them to
+// next.
+func NewRuleFilterLogger(next Logger, rules []FilterRule) *RuleFilterLogger {
+	return &RuleFilterLogger{
+		next:  next,
+		rules: append([]FilterRule(nil), rules...),
+	}
+}
+
+// Log forwards the events which meet the minimum severity of the first rule they match.
+func (l *RuleFilterLogger) Log(evs ...Event) {
+	filtered := make([]Event, 0, len(evs))
+	for _, e := range evs {
+		if l.enabled(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) > 0 {
+		l.next.Log(filtered...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *RuleFilterLogger) Flush() error {
+	return l.next.Flush()
+}
+
+func (l *RuleFilterLogger) enabled(e Event) bool {
+	for _, r := range l.rules {
+		if r.matches(e) {
+			return e.Severity >= r.MinSeverity
+		}
+	}
+	return true
+}
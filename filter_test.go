@@ -0,0 +1,166 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelFilterLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewLevelFilterLogger(next, InfoSeverity)
+
+	logger.Log(
+		Eventf(DebugSeverity, context.Background(), "debug"),
+		Eventf(InfoSeverity, context.Background(), "info"),
+		Eventf(ErrorSeverity, context.Background(), "error"))
+
+	events := next.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "info", events[0].Message)
+	assert.Equal(t, "error", events[1].Message)
+}
+
+func TestLevelFilterLoggerContextOverride(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewLevelFilterLogger(next, InfoSeverity)
+
+	verbose := WithMinSeverity(context.Background(), TraceSeverity)
+	quiet := WithMinSeverity(context.Background(), ErrorSeverity)
+	logger.Log(
+		Eventf(TraceSeverity, verbose, "verbose trace"),
+		Eventf(WarnSeverity, quiet, "quiet warn"),
+		Eventf(TraceSeverity, context.Background(), "trace"))
+
+	events := next.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "verbose trace", events[0].Message)
+}
+
+func TestLevelFilterLoggerEnabled(t *testing.T) {
+	logger := NewLevelFilterLogger(NewInMemoryLogger(), InfoSeverity)
+	assert.False(t, logger.Enabled(DebugSeverity))
+	assert.True(t, logger.Enabled(InfoSeverity))
+	assert.True(t, logger.Enabled(ErrorSeverity))
+
+	nested := NewLevelFilterLogger(NewLevelFilterLogger(NewInMemoryLogger(), ErrorSeverity), InfoSeverity)
+	assert.False(t, nested.Enabled(WarnSeverity))
+	assert.True(t, nested.Enabled(ErrorSeverity))
+}
+
+func TestSelectorLogger(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		selector map[string]string
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			desc:     "match",
+			selector: map[string]string{"service": "payments", "region": "eu"},
+			labels:   map[string]string{"service": "payments", "region": "eu", "other": "x"},
+			expected: true,
+		},
+		{
+			desc:     "non-match",
+			selector: map[string]string{"service": "payments", "region": "eu"},
+			labels:   map[string]string{"service": "payments", "region": "us"},
+			expected: false,
+		},
+		{
+			desc:     "missing key",
+			selector: map[string]string{"service": "payments", "region": "eu"},
+			labels:   map[string]string{"service": "payments"},
+			expected: false,
+		},
+		{
+			desc:     "no labels",
+			selector: map[string]string{"service": "payments"},
+			labels:   nil,
+			expected: false,
+		},
+		{
+			desc:     "wildcard present",
+			selector: map[string]string{"region": SelectorWildcard},
+			labels:   map[string]string{"region": "us"},
+			expected: true,
+		},
+		{
+			desc:     "wildcard missing",
+			selector: map[string]string{"region": SelectorWildcard},
+			labels:   map[string]string{"service": "payments"},
+			expected: false,
+		},
+		{
+			desc:     "empty selector",
+			selector: nil,
+			labels:   nil,
+			expected: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			next := NewInMemoryLogger()
+			logger := NewSelectorLogger(next, tC.selector)
+
+			e := Eventf(InfoSeverity, context.Background(), "foo")
+			e.Labels = tC.labels
+			logger.Log(e)
+
+			if tC.expected {
+				assert.Len(t, next.Events(), 1)
+			} else {
+				assert.Empty(t, next.Events())
+			}
+		})
+	}
+}
+
+func TestRuleFilterLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewRuleFilterLogger(next, []FilterRule{
+		{Key: "feature", Value: "new_checkout", MinSeverity: DebugSeverity},
+		{Key: "attempt", Value: "3", MinSeverity: TraceSeverity},
+		{Key: "noisy", Value: SelectorWildcard, MinSeverity: ErrorSeverity},
+		{MinSeverity: InfoSeverity},
+	})
+	ctx := context.Background()
+
+	logger.Log(
+		// The label matches the first rule
+		Eventf(DebugSeverity, ctx, "Checkout debug").WithLabel("feature", "new_checkout"),
+		// As does the metadata, and the first match wins over the wildcard rule
+		Eventf(DebugSeverity, ctx, "Checkout noisy debug", map[string]string{"feature": "new_checkout", "noisy": "yes"}),
+		// Non-string metadata is compared in its string form
+		Eventf(TraceSeverity, ctx, "Retry trace", map[string]interface{}{"attempt": 3}),
+		Eventf(TraceSeverity, ctx, "First attempt trace", map[string]interface{}{"attempt": 1}),
+		Eventf(WarnSeverity, ctx, "Noisy warning", map[string]string{"noisy": ""}),
+		Eventf(ErrorSeverity, ctx, "Noisy error").WithLabel("noisy", "true"),
+		// Anything else falls through to the default
+		Eventf(DebugSeverity, ctx, "Other checkout debug").WithLabel("feature", "old_checkout"),
+		Eventf(InfoSeverity, ctx, "Info"),
+	)
+
+	assert.Equal(t, []string{
+		"Checkout debug",
+		"Checkout noisy debug",
+		"Retry trace",
+		"Noisy error",
+		"Info",
+	}, messages(next.Events()))
+}
+
+func TestRuleFilterLoggerWithoutDefault(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewRuleFilterLogger(next, []FilterRule{
+		{Key: "feature", Value: "new_checkout", MinSeverity: WarnSeverity},
+	})
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "Checkout info").WithLabel("feature", "new_checkout"),
+		Eventf(TraceSeverity, context.Background(), "Unmatched trace"),
+	)
+	assert.Equal(t, []string{"Unmatched trace"}, messages(next.Events()))
+}
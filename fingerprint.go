@@ -0,0 +1,37 @@
+package slog
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// Fingerprint returns a stable hash of the event's shape: its severity, its message before interpolation
+// (OriginalMessage, or Message if that isn't set) and the keys, but not values, of its metadata. Events logged from
+// the same call site, such as "user %s login failed" for different users, have the same fingerprint, so it is suitable
+// for grouping similar events. The fingerprint is the same across processes and versions of this package, and doesn't
+// depend on the names set with SetSeverityNames.
+func (e Event) Fingerprint() string {
+	msg := e.OriginalMessage
+	if msg == "" {
+		msg = e.Message
+	}
+	keys := make([]string, 0, len(e.Metadata))
+	for k := range e.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Each part is terminated by a NUL, so that different splits of the same bytes hash differently
+	h := fnv.New64a()
+	h.Write([]byte(strconv.Itoa(int(e.Severity))))
+	h.Write([]byte{0})
+	h.Write([]byte(msg))
+	h.Write([]byte{0})
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
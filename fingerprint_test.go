@@ -0,0 +1,39 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFingerprint(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := func(sev Severity, msg string, params ...interface{}) string {
+		return Eventf(sev, ctx, msg, params...).Fingerprint()
+	}
+
+	base := fingerprint(WarnSeverity, "user %s login failed", "u1", map[string]string{"attempt": "1"})
+	assert.Len(t, base, 16)
+	assert.Equal(t, base, fingerprint(WarnSeverity, "user %s login failed", "u2", map[string]string{"attempt": "2"}))
+
+	assert.NotEqual(t, base, fingerprint(ErrorSeverity, "user %s login failed", "u1", map[string]string{"attempt": "1"}))
+	assert.NotEqual(t, base, fingerprint(WarnSeverity, "user %s logout failed", "u1", map[string]string{"attempt": "1"}))
+	assert.NotEqual(t, base, fingerprint(WarnSeverity, "user %s login failed", "u1", map[string]string{"retry": "1"}))
+	assert.NotEqual(t, base, fingerprint(WarnSeverity, "user %s login failed", "u1"))
+
+	// Events constructed directly fall back to their message
+	e := Event{Severity: InfoSeverity, Message: "Constructed"}
+	assert.Equal(t, fingerprint(InfoSeverity, "Constructed"), e.Fingerprint())
+
+	// Renaming severities doesn't change fingerprints
+	SetSeverityNames(map[Severity]string{WarnSeverity: "WARNING"})
+	renamed := fingerprint(WarnSeverity, "user %s login failed", "u1", map[string]string{"attempt": "1"})
+	SetSeverityNames(nil)
+	assert.Equal(t, base, renamed)
+
+	// Keys can't be confused with each other or with the message
+	a := Event{Message: "m", Metadata: map[string]interface{}{"ab": 1}}
+	b := Event{Message: "m", Metadata: map[string]interface{}{"a": 1, "b": 2}}
+	assert.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+}
@@ -0,0 +1,157 @@
+package slog
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signalFlushTimeout bounds how long FlushOnSignal waits for the default logger to flush.
+const signalFlushTimeout = 5 * time.Second
+
+// FlushWithContext flushes the logger, returning early with the context's error if the context is done before the
+// flush completes. The flush itself is not interrupted, and continues in the background.
+func FlushWithContext(ctx context.Context, l Logger) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Flush()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type registeredFlusher struct {
+	id uint64
+	l  Logger
+}
+
+var (
+	flushers   []registeredFlusher
+	flushersID uint64
+	flushersM  sync.Mutex
+)
+
+// RegisterFlusher adds the logger to those flushed by FlushAll, so that an application which creates several loggers
+// doesn't have to track them itself in order to flush them on shutdown. It returns a function which removes it again.
+func RegisterFlusher(l Logger) func() {
+	flushersM.Lock()
+	defer flushersM.Unlock()
+	flushersID++
+	id := flushersID
+	flushers = append(flushers, registeredFlusher{id, l})
+
+	return func() {
+		flushersM.Lock()
+		defer flushersM.Unlock()
+		for i, f := range flushers {
+			if f.id == id {
+				flushers = append(flushers[:i:i], flushers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// FlushAll flushes every logger registered with RegisterFlusher concurrently, returning once they have all finished
+// or the context is done. The errors from all the loggers are combined: if more than one failed, the result's
+// Unwrap method returns each of their errors, in the order the loggers were registered.
+func FlushAll(ctx context.Context) error {
+	flushersM.Lock()
+	fs := flushers
+	flushersM.Unlock()
+
+	errs := make([]error, len(fs))
+	var wg sync.WaitGroup
+	for i, f := range fs {
+		wg.Add(1)
+		go func(i int, l Logger) {
+			defer wg.Done()
+			errs[i] = FlushWithContext(ctx, l)
+		}(i, f.l)
+	}
+	wg.Wait()
+
+	var result flushErrors
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil
+	case 1:
+		return result[0]
+	}
+	return result
+}
+
+// flushErrors is the errors of several loggers which failed to flush. It behaves like an error created by errors.Join.
+type flushErrors []error
+
+func (e flushErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e flushErrors) Unwrap() []error {
+	return e
+}
+
+// FlushOnSignal flushes the default logger when one of the given signals is received, such as SIGTERM during
+// shutdown, and then lets the signal take effect: the handler is uninstalled and the signal is raised again. If nothing
+// else in the process is notified of it, its default behaviour applies, so the process terminates as it would have
+// without the handler. Handlers the application has installed with signal.Notify are left in place, and receive the
+// signal again when it is raised. The flush waits at most a few seconds, so that a hung logger can't block shutdown.
+// It returns a function which uninstalls the handler.
+//
+// This is opt-in. Applications which handle the signals themselves, rather than letting them terminate the process,
+// should instead flush with FlushWithContext from their own handler.
+func FlushOnSignal(signals ...os.Signal) func() {
+	ch := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(ch, signals...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			if l := DefaultLogger(); l != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), signalFlushTimeout)
+				FlushWithContext(ctx, l)
+				cancel()
+			}
+			// Only this handler's channel is stopped: signal.Reset would also remove the application's handlers
+			signal.Stop(ch)
+			reraise(sig)
+		case <-stop:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(stop)
+		})
+	}
+}
+
+// reraise sends the signal to the current process, once FlushOnSignal's handler for it has been stopped. Where that isn't
+// possible (on Windows, only Kill can be sent), the process exits instead.
+func reraise(sig os.Signal) {
+	if p, err := os.FindProcess(os.Getpid()); err == nil && p.Signal(sig) == nil {
+		return
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,95 @@
+//go:build !windows
+// +build !windows
+
+package slog
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// printingFlushLogger reports each flush on stdout, so that a parent process can observe it.
+type printingFlushLogger struct {
+	*InMemoryLogger
+}
+
+func (l printingFlushLogger) Flush() error {
+	fmt.Println("flushed")
+	return nil
+}
+
+// TestFlushOnSignal runs the test binary again as a child process, which installs the handler and sends itself
+// SIGTERM. The child must flush, and then still be terminated by the signal.
+func TestFlushOnSignal(t *testing.T) {
+	if os.Getenv("SLOG_FLUSH_ON_SIGNAL_CHILD") == "1" {
+		SetDefaultLogger(printingFlushLogger{NewInMemoryLogger()})
+		FlushOnSignal(syscall.SIGTERM)
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+		time.Sleep(10 * time.Second)
+		fmt.Println("survived")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFlushOnSignal$")
+	cmd.Env = append(os.Environ(), "SLOG_FLUSH_ON_SIGNAL_CHILD=1")
+	out, err := cmd.Output()
+	require.Error(t, err)
+	status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	require.True(t, ok)
+	assert.True(t, status.Signaled())
+	assert.Equal(t, syscall.SIGTERM, status.Signal())
+	assert.Contains(t, string(out), "flushed")
+	assert.NotContains(t, string(out), "survived")
+}
+
+func TestFlushOnSignalUninstall(t *testing.T) {
+	uninstall := FlushOnSignal(syscall.SIGUSR1)
+	uninstall()
+	uninstall()
+}
+
+// signalFlushLogger reports each flush on a channel.
+type signalFlushLogger struct {
+	*InMemoryLogger
+	flushed chan struct{}
+}
+
+func (l signalFlushLogger) Flush() error {
+	l.flushed <- struct{}{}
+	return nil
+}
+
+func TestFlushOnSignalKeepsAppHandlers(t *testing.T) {
+	appCh := make(chan os.Signal, 2)
+	signal.Notify(appCh, syscall.SIGUSR2)
+	defer signal.Stop(appCh)
+
+	logger := signalFlushLogger{NewInMemoryLogger(), make(chan struct{}, 1)}
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+	defer FlushOnSignal(syscall.SIGUSR2)()
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	select {
+	case <-logger.flushed:
+	case <-time.After(time.Second):
+		t.Fatal("not flushed")
+	}
+	// The application's handler gets both the original signal and the one raised again after the flush
+	for i := 0; i < 2; i++ {
+		select {
+		case <-appCh:
+		case <-time.After(time.Second):
+			t.Fatalf("application's handler got %d signals", i)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingFlushLogger struct {
+	*InMemoryLogger
+	unblock chan struct{}
+}
+
+func (l *blockingFlushLogger) Flush() error {
+	<-l.unblock
+	return nil
+}
+
+func TestFlushWithContext(t *testing.T) {
+	logger := &blockingFlushLogger{
+		InMemoryLogger: NewInMemoryLogger(),
+		unblock:        make(chan struct{}),
+	}
+	defer close(logger.unblock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, FlushWithContext(ctx, logger))
+
+	assert.NoError(t, FlushWithContext(context.Background(), NewInMemoryLogger()))
+}
+
+type registeredFlushLogger struct {
+	*InMemoryLogger
+	flushes int32
+	err     error
+}
+
+func (l *registeredFlushLogger) Flush() error {
+	atomic.AddInt32(&l.flushes, 1)
+	return l.err
+}
+
+func TestFlushAll(t *testing.T) {
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	loggers := []*registeredFlushLogger{
+		{InMemoryLogger: NewInMemoryLogger(), err: errA},
+		{InMemoryLogger: NewInMemoryLogger()},
+		{InMemoryLogger: NewInMemoryLogger(), err: errB},
+	}
+	for _, l := range loggers {
+		defer RegisterFlusher(l)()
+	}
+
+	err := FlushAll(context.Background())
+	assert.EqualError(t, err, "a failed\nb failed")
+	assert.True(t, errors.Is(err, errA))
+	assert.True(t, errors.Is(err, errB))
+	for _, l := range loggers {
+		assert.EqualValues(t, 1, atomic.LoadInt32(&l.flushes))
+	}
+
+	loggers[2].err = nil
+	assert.Equal(t, errA, FlushAll(context.Background()))
+	loggers[0].err = nil
+	assert.NoError(t, FlushAll(context.Background()))
+}
+
+func TestFlushAllUnregister(t *testing.T) {
+	l := &registeredFlushLogger{InMemoryLogger: NewInMemoryLogger()}
+	unregister := RegisterFlusher(l)
+	unregister()
+	unregister()
+
+	assert.NoError(t, FlushAll(context.Background()))
+	assert.Zero(t, atomic.LoadInt32(&l.flushes))
+}
+
+func TestFlushAllDeadline(t *testing.T) {
+	blocked := &blockingFlushLogger{
+		InMemoryLogger: NewInMemoryLogger(),
+		unblock:        make(chan struct{}),
+	}
+	defer close(blocked.unblock)
+	defer RegisterFlusher(blocked)()
+	l := &registeredFlushLogger{InMemoryLogger: NewInMemoryLogger()}
+	defer RegisterFlusher(l)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, FlushAll(ctx))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&l.flushes))
+}
@@ -1,41 +1,230 @@
 package slog
 
 import (
-	"regexp"
-	"strconv"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
 )
 
-var formatterRe = regexp.MustCompile(`%` +
-	`[\+\-# 0]*` + // Flags
-	`(?:\d*\.|\[(\d+)\]\*\.)?(?:\d+|\[(\d+)\]\*)?` + // Width and precision
-	`(?:\[(\d+)\])?` + // Argument index
-	`[vTtbcdoOqxXUeEfFgGsp%]`, // Verb
-)
+// formatValidation is non-zero if Eventf should report malformed format strings.
+var formatValidation int32
+
+// A FmtVerb describes a use of an operand by a format string.
+type FmtVerb struct {
+	// Pos is the byte offset in the format string of the % which starts the directive using the operand.
+	Pos int
+	// Verb is the verb the operand is formatted with, or '*' if it is used as a width or precision.
+	Verb rune
+	// Arg is the index of the operand among those passed to fmt.Sprintf.
+	Arg int
+}
+
+// ParseFormat returns each use of an operand by the format string msg, in the order fmt.Sprintf would consume them. A
+// directive with its width or precision taken from operands ("%*.*f") uses several, and an operand can be used more
+// than once with explicit argument indexes ("%[1]d %[1]x"). It mirrors the parsing in fmt's doPrintf, as if there were
+// always enough operands; directives which consume no operand, like %% or a bad index, are omitted.
+func ParseFormat(msg string) []FmtVerb {
+	var verbs []FmtVerb
+	scanFmtOperands(msg, false, func(pos, arg int, verb rune) {
+		verbs = append(verbs, FmtVerb{Pos: pos, Verb: verb, Arg: arg})
+	})
+	return verbs
+}
 
+// countFmtOperands returns the number of operands which the input expects, for the heuristic which decides whether
+// trailing params are metadata: one more than the highest Arg returned by ParseFormat, except that directives with
+// verbs fmt doesn't know (such as the "%," of "50%, failing") are ignored. fmt would consume an operand for them, but
+// they are almost always a literal percent sign rather than a mistyped verb. As it is called for every event, it
+// scans the input without building the slice.
 func countFmtOperands(input string) int {
-	count, point := 0, 0
-	for _, match := range formatterRe.FindAllStringSubmatch(input, -1) {
-		if match[0] == "%%" {
-			// Deliberately match the regexp on %% (to prevent overlapping matches), but stop them here
-			continue
+	count := 0
+	scanFmtOperands(input, true, func(pos, arg int, verb rune) {
+		if arg+1 > count {
+			count = arg + 1
 		}
+	})
+	return count
+}
 
-		for _, flag := range match[1:] {
-			if flag == "" {
-				continue
-			} else if i, err := strconv.Atoi(flag); err == nil && i > 0 {
-				point = i
-				if point > count {
-					count = point
-				}
+// fmtVerbs are the verbs fmt.Sprintf knows.
+const fmtVerbs = "vTtbcdoOqxXUeEfFgGsp"
+
+// scanFmtOperands calls visit for each use of an operand by the input, as described by ParseFormat. If skipBadVerbs is
+// set, directives with unknown verbs are treated as if they consumed no operand.
+func scanFmtOperands(input string, skipBadVerbs bool, visit func(pos, arg int, verb rune)) {
+	argNum, start := 0, 0
+	// consume records that the operand at argNum is used, and moves on to the next one
+	consume := func(verb rune) {
+		visit(start, argNum, verb)
+		argNum++
+	}
+
+	end := len(input)
+	for i := 0; i < end; {
+		goodArgNum := true
+		for i < end && input[i] != '%' {
+			i++
+		}
+		if i >= end {
+			break
+		}
+		start = i
+		i++
+
+		// Flags
+		for i < end && strings.IndexByte("#0+- ", input[i]) >= 0 {
+			i++
+		}
+
+		// Explicit argument index
+		var afterIndex bool
+		argNum, i, afterIndex, goodArgNum = fmtArgNumber(input, i, argNum, goodArgNum)
+
+		// Width
+		if i < end && input[i] == '*' {
+			i++
+			consume('*')
+			afterIndex = false
+		} else {
+			var present bool
+			_, present, i = fmtParseNum(input, i, end)
+			if afterIndex && present { // "%[3]2d"
+				goodArgNum = false
 			}
 		}
-		if match[3] == "" {
-			point++
+
+		// Precision
+		if i+1 < end && input[i] == '.' {
+			i++
+			if afterIndex { // "%[3].2d"
+				goodArgNum = false
+			}
+			argNum, i, afterIndex, goodArgNum = fmtArgNumber(input, i, argNum, goodArgNum)
+			if i < end && input[i] == '*' {
+				i++
+				consume('*')
+				afterIndex = false
+			} else {
+				_, _, i = fmtParseNum(input, i, end)
+			}
+		}
+
+		if !afterIndex {
+			argNum, i, _, goodArgNum = fmtArgNumber(input, i, argNum, goodArgNum)
+		}
+
+		if i >= end {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(input[i:])
+		i += size
+		if skipBadVerbs && (verb >= utf8.RuneSelf || strings.IndexByte(fmtVerbs, byte(verb)) < 0) {
+			continue
 		}
-		if point > count {
-			count = point
+		if verb != '%' && goodArgNum {
+			consume(verb)
 		}
 	}
-	return count
+}
+
+// fmtArgNumber parses an explicit argument index ("[n]") at input[i:], if there is one, returning the new argument
+// number, the index of the next byte to process, whether an index was found, and whether the argument number is
+// still valid.
+func fmtArgNumber(input string, i, argNum int, goodArgNum bool) (int, int, bool, bool) {
+	if i >= len(input) || input[i] != '[' {
+		return argNum, i, false, goodArgNum
+	}
+	// Like fmt, only the opening bracket is skipped if there are fewer than three bytes or no closing bracket
+	if len(input)-i < 3 {
+		return argNum, i + 1, false, false
+	}
+	for j := i + 1; j < len(input); j++ {
+		if input[j] != ']' {
+			continue
+		}
+		n, ok, newj := fmtParseNum(input, i+1, j)
+		if !ok || newj != j {
+			return argNum, j + 1, false, false
+		}
+		if n < 1 {
+			return argNum, j + 1, true, false
+		}
+		return n - 1, j + 1, true, goodArgNum
+	}
+	return argNum, i + 1, false, false
+}
+
+// fmtParseNum parses a decimal number from input[start:end], like fmt. If the number is implausibly large, it
+// returns end as the index of the next byte to process.
+func fmtParseNum(input string, start, end int) (num int, isNum bool, newi int) {
+	if start >= end {
+		return 0, false, end
+	}
+	for newi = start; newi < end && '0' <= input[newi] && input[newi] <= '9'; newi++ {
+		if num > 1e6 {
+			return 0, false, end
+		}
+		num = num*10 + int(input[newi]-'0')
+		isNum = true
+	}
+	return num, isNum, newi
+}
+
+// ValidateFormat checks that the format string msg consumes exactly nParams operands, returning a descriptive error
+// if it does not.
+func ValidateFormat(msg string, nParams int) error {
+	operands := countFmtOperands(msg)
+	switch {
+	case operands > nParams:
+		return fmt.Errorf("format %q expects %d operands but only %d params were given", msg, operands, nParams)
+	case operands < nParams:
+		return fmt.Errorf("format %q expects %d operands but %d params were given", msg, operands, nParams)
+	default:
+		return nil
+	}
+}
+
+// SetFormatValidation enables or disables format validation in Eventf. When enabled, an event whose format string
+// expects more operands than it was given causes an additional Warn event to be sent to the default Logger. This is
+// intended for debugging, and is disabled by default.
+func SetFormatValidation(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&formatValidation, v)
+}
+
+func formatValidationEnabled() bool {
+	return atomic.LoadInt32(&formatValidation) != 0
+}
+
+// FormatErrorMode determines how Eventf handles a format string which expects more operands than it was given.
+type FormatErrorMode int32
+
+const (
+	// FormatErrorKeep interpolates as many operands as were given, leaving fmt's "%!s(MISSING)" markers for the rest.
+	// This is the default.
+	FormatErrorKeep FormatErrorMode = iota
+	// FormatErrorRaw uses the format string uninterpolated as the event's message, and records the mismatch in its
+	// metadata under FormatErrorMetadataKey.
+	FormatErrorRaw
+)
+
+// FormatErrorMetadataKey is the metadata key for the description of a mismatch between an event's format string and
+// its params, added when SetOnFormatError is set to FormatErrorRaw.
+const FormatErrorMetadataKey = "format_error"
+
+// onFormatError is the FormatErrorMode set with SetOnFormatError.
+var onFormatError int32
+
+// SetOnFormatError sets how Eventf handles a format string which expects more operands than it was given (or, for
+// EventfWithMetadata, any mismatch between them). Params are still extracted as metadata and errors in either case.
+func SetOnFormatError(mode FormatErrorMode) {
+	atomic.StoreInt32(&onFormatError, int32(mode))
+}
+
+func formatErrorMode() FormatErrorMode {
+	return FormatErrorMode(atomic.LoadInt32(&onFormatError))
 }
@@ -0,0 +1,73 @@
+//go:build go1.18
+// +build go1.18
+
+package slog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// maxFuzzOperands bounds the number of operands considered by FuzzCountFmtOperands.
+const maxFuzzOperands = 10
+
+// referenceFmtOperands returns the number of operands fmt consumes when formatting the input: the fewest for which it
+// produces the same output as when given maxFuzzOperands operands.
+func referenceFmtOperands(input string) int {
+	// Integers of distinct values and types, so every verb (including %T) distinguishes them, which are also valid
+	// widths and precisions
+	args := []interface{}{int(1), int8(2), int16(3), int32(4), int64(5), uint(6), uint8(7), uint16(8), uint32(9), uint64(10)}
+	sprintf := func(n int) string {
+		out := fmt.Sprintf(input, args[:n]...)
+		if i := strings.Index(out, "%!(EXTRA "); i >= 0 {
+			out = out[:i]
+		}
+		return out
+	}
+
+	expected := sprintf(maxFuzzOperands)
+	for n := 0; n < maxFuzzOperands; n++ {
+		if sprintf(n) == expected {
+			return n
+		}
+	}
+	return maxFuzzOperands
+}
+
+func FuzzCountFmtOperands(f *testing.F) {
+	for _, seed := range []string{
+		`%%`, `%s`, `%09d`, `%9.2f`, `%%s %s %s`, `%d %d %#[1]x %#x`, `%[2]d %[1]d`, `%[3]*.[2]*[1]f`,
+		`%[3]*.[2]*[1]f %[3]*.[2]*[1]f %s`, `%*d`, `%.*f`, `%-+# 0v`, `%z`, `100%`, `%[0]d`, `%10000010A`,
+		`%[2]%%d`, `%[2]*%`, `%[2]*[0A`, `%[7]*`, `%[2]%%T`, `Disk at 50%, failing`, `%s at 50%, failing: %s`, `%1*`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if strings.Contains(input, "%!(") {
+			t.Skip("input contains fmt's error markers")
+		}
+		// ParseFormat mirrors fmt exactly, and countFmtOperands only differs from it by ignoring unknown verbs. A '*'
+		// may be a width or precision, or an unknown verb, so is treated as if it could be either.
+		parsed, unknown := 0, false
+		for _, v := range ParseFormat(input) {
+			if v.Arg+1 > parsed {
+				parsed = v.Arg + 1
+			}
+			unknown = unknown || !strings.ContainsRune(fmtVerbs, v.Verb)
+		}
+		if actual := countFmtOperands(input); actual > parsed || (actual < parsed && !unknown) {
+			t.Errorf("countFmtOperands(%q) = %d, but ParseFormat uses %d operands", input, actual, parsed)
+		}
+		if parsed > maxFuzzOperands {
+			t.Skip("input consumes too many operands")
+		}
+		expected := referenceFmtOperands(input)
+		// The operand used by a width or precision star doesn't affect the output if the directive's verb doesn't
+		// print an operand, so the reference may miss them
+		if parsed < expected || (parsed > expected && !strings.Contains(input, "*")) {
+			t.Errorf("ParseFormat(%q) uses %d operands, but fmt consumes %d", input, parsed, expected)
+		}
+	})
+}
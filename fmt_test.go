@@ -1,6 +1,8 @@
 package slog
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,9 +49,96 @@ func TestCountFmtOperands(t *testing.T) {
 		`%s %s %d`:                         3,
 		`%[2]d %[1]d`:                      2,
 		`%[3]*.[2]*[1]f`:                   3,
-		`%[3]*.[2]*[1]f %[3]*.[2]*[1]f %s`: 3}
+		`%[3]*.[2]*[1]f %[3]*.[2]*[1]f %s`: 3,
+		`%*d`:                              2,
+		`%.*f`:                             2,
+		`%z`:                               0,
+		`Disk at 50%, failing`:             0,
+		`%s at 50%, failing`:               1,
+		`%s at 50%, failing: %s`:           2,
+		`%[0]d`:                            0,
+		`%[2]%%d`:                          2,
+		`100%`:                             0}
 
 	for input, count := range cases {
 		assert.Equal(t, count, countFmtOperands(input), input)
 	}
 }
+
+func TestParseFormat(t *testing.T) {
+	cases := map[string][]FmtVerb{
+		`%%`:           nil,
+		`%%s`:          nil,
+		`100%`:         nil,
+		`%v`:           {{0, 'v', 0}},
+		`%#v`:          {{0, 'v', 0}},
+		`% d`:          {{0, 'd', 0}},
+		`%09d`:         {{0, 'd', 0}},
+		`%9.2f`:        {{0, 'f', 0}},
+		`%z`:           {{0, 'z', 0}},
+		`%%s %s %s`:    {{4, 's', 0}, {7, 's', 1}},
+		`%s %% %%s %s`: {{0, 's', 0}, {10, 's', 1}},
+		`foo %s %d`:    {{4, 's', 0}, {7, 'd', 1}},
+		`%[2]d %[1]d`:  {{0, 'd', 1}, {6, 'd', 0}},
+		`%d %d %#[1]x %#x`: {
+			{0, 'd', 0}, {3, 'd', 1}, {6, 'x', 0}, {13, 'x', 1},
+		},
+		`%*d`:  {{0, '*', 0}, {0, 'd', 1}},
+		`%.*f`: {{0, '*', 0}, {0, 'f', 1}},
+		`%[3]*.[2]*[1]f %s`: {
+			{0, '*', 2}, {0, '*', 1}, {0, 'f', 0}, {15, 's', 1},
+		},
+		`%[0]d`:       nil,
+		`%[2]%%d`:     {{5, 'd', 1}},
+		`日本 %s`:       {{7, 's', 0}},
+		`%s 日本 %[1]q`: {{0, 's', 0}, {10, 'q', 0}},
+	}
+
+	for input, verbs := range cases {
+		assert.Equal(t, verbs, ParseFormat(input), input)
+
+		// countFmtOperands ignores unknown verbs
+		count := 0
+		for _, v := range verbs {
+			if v.Verb != '*' && !strings.ContainsRune(fmtVerbs, v.Verb) {
+				continue
+			}
+			if v.Arg+1 > count {
+				count = v.Arg + 1
+			}
+		}
+		assert.Equal(t, count, countFmtOperands(input), input)
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert.NoError(t, ValidateFormat("foo", 0))
+	assert.NoError(t, ValidateFormat("foo %s %d", 2))
+	assert.NoError(t, ValidateFormat("foo %[2]d %[1]d", 2))
+	assert.EqualError(t, ValidateFormat("foo %s %s", 1),
+		`format "foo %s %s" expects 2 operands but only 1 params were given`)
+	assert.EqualError(t, ValidateFormat("foo %s", 2),
+		`format "foo %s" expects 1 operands but 2 params were given`)
+}
+
+func TestFormatValidationEvent(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Eventf(InfoSeverity, context.Background(), "foo %s %s", "bar")
+	assert.Empty(t, logger.Events())
+
+	SetFormatValidation(true)
+	defer SetFormatValidation(false)
+	Eventf(InfoSeverity, context.Background(), "foo %s %s", "bar")
+	Eventf(InfoSeverity, context.Background(), "foo %s", "bar")
+
+	events := logger.Events()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, WarnSeverity, events[0].Severity)
+		assert.Equal(t, "foo %s %s", events[0].Metadata["format"])
+		assert.Error(t, events[0].Error.(error))
+	}
+}
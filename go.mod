@@ -2,7 +2,4 @@ module github.com/monzo/slog
 
 go 1.13
 
-require (
-	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d
-	github.com/stretchr/testify v1.4.0
-)
+require github.com/stretchr/testify v1.4.0
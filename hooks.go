@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type severityCallback struct {
+	min Severity
+	fn  func(Event)
+}
+
+var (
+	severityCallbacks  map[int]severityCallback
+	nextCallbackID     int
+	severityCallbacksM sync.RWMutex
+)
+
+// OnSeverity registers fn to be called for every event of at least the given severity logged by the package-level
+// logging functions (Log, Info, Error and so on), regardless of which Logger it is sent to. This decouples alerting,
+// such as paging on critical events, from log shipping. Events which the Logger filters out by severity (according to
+// its LevelEnabler implementation, as reported by Enabled) don't trigger the callbacks, and nor do events which are
+// only constructed, or sent to a Logger directly.
+//
+// Each callback is called in its own goroutine so it can't block logging, and any panic in it is recovered. The
+// returned function unregisters the callback.
+func OnSeverity(sev Severity, fn func(Event)) func() {
+	severityCallbacksM.Lock()
+	defer severityCallbacksM.Unlock()
+	if severityCallbacks == nil {
+		severityCallbacks = map[int]severityCallback{}
+	}
+	id := nextCallbackID
+	nextCallbackID++
+	severityCallbacks[id] = severityCallback{
+		min: sev,
+		fn:  fn,
+	}
+
+	return func() {
+		severityCallbacksM.Lock()
+		defer severityCallbacksM.Unlock()
+		delete(severityCallbacks, id)
+	}
+}
+
+// hasSeverityCallbacks reports whether any callbacks are registered for events of the given severity.
+func hasSeverityCallbacks(sev Severity) bool {
+	severityCallbacksM.RLock()
+	defer severityCallbacksM.RUnlock()
+	for _, cb := range severityCallbacks {
+		if sev >= cb.min {
+			return true
+		}
+	}
+	return false
+}
+
+// logAndNotify sends the events to l, and then starts the severity callbacks for each of them which l doesn't filter
+// out.
+func logAndNotify(l Logger, evs ...Event) {
+	l.Log(evs...)
+	for _, e := range evs {
+		if loggerEnabled(l, e.Context, e.Severity) {
+			runSeverityCallbacks(e)
+		}
+	}
+}
+
+// logLeveled forwards an event to the function of a LeveledLogger, which builds the event itself, notifying the
+// severity callbacks as by notifyLeveled.
+func logLeveled(
+	l Logger, f func(ctx context.Context, msg string, params ...interface{}),
+	sev Severity, ctx context.Context, msg string, params ...interface{}) {
+	notifyLeveled(l, func(ctx context.Context) {
+		f(ctx, msg, params...)
+	}, sev, ctx, msg, params...)
+}
+
+// notifyKey is the context key for the pendingNotification of an event being logged by a LeveledLogger.
+type notifyKey struct{}
+
+// A pendingNotification is carried by the context given to a LeveledLogger or FromErrorLogger, so that the severity
+// callbacks can be given the event it builds with Eventf, rather than an equivalent one built again (which would have
+// a different Id and Sequence, and run extractors twice). Only the first event built with the context claims it.
+type pendingNotification struct {
+	l       Logger
+	claimed int32
+}
+
+func (p *pendingNotification) claim() bool {
+	return atomic.CompareAndSwapInt32(&p.claimed, 0, 1)
+}
+
+// notifyLeveled calls log, which builds and logs an event of about the given severity itself, with a context which
+// lets the severity callbacks be given that event (see notifyBuilt). If log doesn't build the event with Eventf, the
+// callbacks are instead given one built from sev, ctx, msg and params, provided l doesn't filter it out.
+func notifyLeveled(
+	l Logger, log func(ctx context.Context),
+	sev Severity, ctx context.Context, msg string, params ...interface{}) {
+	if !hasSeverityCallbacks(sev) {
+		log(ctx)
+		return
+	}
+	parent := ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	p := &pendingNotification{l: l}
+	log(context.WithValue(parent, notifyKey{}, p))
+	if p.claim() && loggerEnabled(l, ctx, sev) {
+		runSeverityCallbacks(Eventf(sev, ctx, msg, params...))
+	}
+}
+
+// notifyBuilt starts the severity callbacks for an event built by Eventf, if its context carries an unclaimed
+// pendingNotification and the Logger doesn't filter the event out.
+func notifyBuilt(e Event) {
+	p, ok := e.Context.Value(notifyKey{}).(*pendingNotification)
+	if ok && p != nil && p.claim() && loggerEnabled(p.l, e.Context, e.Severity) {
+		runSeverityCallbacks(e)
+	}
+}
+
+// runSeverityCallbacks starts the callbacks registered for the event's severity.
+func runSeverityCallbacks(e Event) {
+	severityCallbacksM.RLock()
+	defer severityCallbacksM.RUnlock()
+	for _, cb := range severityCallbacks {
+		if e.Severity < cb.min {
+			continue
+		}
+		go func(fn func(Event)) {
+			defer func() {
+				recover()
+			}()
+			fn(e)
+		}(cb.fn)
+	}
+}
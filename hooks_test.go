@@ -0,0 +1,159 @@
+package slog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnSeverity(t *testing.T) {
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(NewInMemoryLogger())
+	defer SetDefaultLogger(oldLogger)
+
+	called := make(chan Event, 10)
+	unregister := OnSeverity(CriticalSeverity, func(e Event) {
+		called <- e
+	})
+	defer unregister()
+	unregisterPanicking := OnSeverity(InfoSeverity, func(e Event) {
+		panic("callback failed")
+	})
+	defer unregisterPanicking()
+
+	// Constructing an event doesn't trigger the callbacks; logging it does
+	Eventf(CriticalSeverity, context.Background(), "constructed")
+	Info(context.Background(), "info")
+	Critical(context.Background(), "critical")
+
+	select {
+	case e := <-called:
+		assert.Equal(t, "critical", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+	assert.Empty(t, called)
+
+	unregister()
+	Critical(context.Background(), "unregistered")
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, called)
+}
+
+func TestOnSeverityFiltered(t *testing.T) {
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(NewLevelFilterLogger(NewInMemoryLogger(), ErrorSeverity))
+	defer SetDefaultLogger(oldLogger)
+
+	called := make(chan Event, 10)
+	unregister := OnSeverity(WarnSeverity, func(e Event) {
+		called <- e
+	})
+	defer unregister()
+
+	// The warning is dropped by the filter, so only the error triggers the callback
+	Warn(context.Background(), "filtered")
+	Log(Eventf(WarnSeverity, context.Background(), "filtered"))
+	Error(context.Background(), "logged")
+	select {
+	case e := <-called:
+		assert.Equal(t, "logged", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, called)
+
+	// A lower minimum carried by the context lets the warning through
+	Warn(WithMinSeverity(context.Background(), WarnSeverity), "overridden")
+	select {
+	case e := <-called:
+		assert.Equal(t, "overridden", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestOnSeverityLeveledLogger(t *testing.T) {
+	logger := &testLogLeveledLogger{t: t}
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	called := make(chan Event, 10)
+	unregister := OnSeverity(ErrorSeverity, func(e Event) {
+		called <- e
+	})
+	defer unregister()
+
+	Error(context.Background(), "failed %d times", 3)
+	select {
+	case e := <-called:
+		assert.Equal(t, "failed 3 times", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+	assert.Len(t, logger.items, 1)
+}
+
+// eventfLeveledLogger is a LeveledLogger which builds its events with Eventf, and keeps them in memory.
+type eventfLeveledLogger struct {
+	*InMemoryLogger
+}
+
+func (l eventfLeveledLogger) Critical(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(CriticalSeverity, ctx, msg, params...))
+}
+
+func (l eventfLeveledLogger) Error(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(ErrorSeverity, ctx, msg, params...))
+}
+
+func (l eventfLeveledLogger) Warn(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(WarnSeverity, ctx, msg, params...))
+}
+
+func (l eventfLeveledLogger) Info(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(InfoSeverity, ctx, msg, params...))
+}
+
+func (l eventfLeveledLogger) Debug(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(DebugSeverity, ctx, msg, params...))
+}
+
+func (l eventfLeveledLogger) Trace(ctx context.Context, msg string, params ...interface{}) {
+	l.Log(Eventf(TraceSeverity, ctx, msg, params...))
+}
+
+func TestOnSeverityLeveledLoggerGetsLoggedEvent(t *testing.T) {
+	logger := eventfLeveledLogger{NewInMemoryLogger()}
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	called := make(chan Event, 10)
+	unregister := OnSeverity(ErrorSeverity, func(e Event) {
+		called <- e
+	})
+	defer unregister()
+
+	Error(context.Background(), "failed %d times", 3)
+	Warn(context.Background(), "slow")
+	events := logger.Events()
+	if assert.Len(t, events, 2) {
+		select {
+		case e := <-called:
+			assert.Equal(t, events[0].Id, e.Id)
+			assert.Equal(t, events[0].Sequence, e.Sequence)
+		case <-time.After(time.Second):
+			t.Fatal("callback not called")
+		}
+	}
+	select {
+	case e := <-called:
+		t.Fatalf("callback called more than once, with %q", e.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,212 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// An HTTPLogger buffers events and POSTs them in batches, as a JSON array, to an ingest URL. Batches are sent in order
+// by a background goroutine, so logging never waits for the network. If the endpoint can't keep up, at most a fixed
+// number of batches are queued, and batches past that are dropped and counted.
+type HTTPLogger struct {
+	// failures and dropped are accessed atomically, so come first to be 64-bit aligned on 32-bit platforms.
+	failures uint64
+	dropped  uint64
+	writeErrors
+	url        string
+	client     *http.Client
+	batchSize  int
+	maxQueued  int
+	maxRetries int
+	backoff    time.Duration
+
+	m sync.Mutex
+	// sent is signalled, with m held, when the sending goroutine stops.
+	sent    *sync.Cond
+	pending []Event
+	// queue holds the batches waiting to be sent, in order.
+	queue [][]Event
+	// sending is set while a goroutine is sending the queue. There is only ever one, so batches arrive in order.
+	sending bool
+}
+
+// An HTTPOption configures an HTTPLogger.
+type HTTPOption func(*HTTPLogger)
+
+// WithHTTPClient makes an HTTPLogger send its requests using c. By default, a client with a 10 second timeout is used.
+func WithHTTPClient(c *http.Client) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.client = c
+	}
+}
+
+// WithHTTPBatchSize makes an HTTPLogger send a batch as soon as n events are pending. By default, a batch is sent
+// every 100 events; zero or less means batches are only sent on Flush.
+func WithHTTPBatchSize(n int) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.batchSize = n
+	}
+}
+
+// WithHTTPQueueSize makes an HTTPLogger queue at most n batches while it is waiting for the endpoint; a batch filled
+// while the queue is full is dropped. By default, 100 batches are queued; zero or less means the queue is unbounded.
+// Flush always queues the pending events, regardless of the limit.
+func WithHTTPQueueSize(n int) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.maxQueued = n
+	}
+}
+
+// WithHTTPRetries makes an HTTPLogger retry a batch up to n times after a network error or 5xx response, waiting
+// backoff before the first retry and doubling the wait each time after. By default, a batch is retried 3 times,
+// starting at 100ms.
+func WithHTTPRetries(n int, backoff time.Duration) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.maxRetries = n
+		l.backoff = backoff
+	}
+}
+
+// NewHTTPLogger creates an HTTPLogger which POSTs batches of events to url.
+func NewHTTPLogger(url string, opts ...HTTPOption) *HTTPLogger {
+	l := &HTTPLogger{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		batchSize:  100,
+		maxQueued:  100,
+		maxRetries: 3,
+		backoff:    100 * time.Millisecond,
+	}
+	l.sent = sync.NewCond(&l.m)
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log buffers the events, and queues a batch to be sent in the background if the batch size has been reached. If the
+// queue is full, the batch is dropped instead. Any error is reported by the next call to Flush.
+func (l *HTTPLogger) Log(evs ...Event) {
+	if len(evs) == 0 {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.pending = append(l.pending, evs...)
+	if l.batchSize > 0 && len(l.pending) >= l.batchSize {
+		if l.maxQueued > 0 && len(l.queue) >= l.maxQueued {
+			atomic.AddUint64(&l.dropped, uint64(len(l.pending)))
+			l.pending = nil
+			return
+		}
+		l.enqueue()
+	}
+}
+
+// Flush sends any pending events, waits for every queued batch to be sent, and returns the first error encountered
+// since the last Flush.
+func (l *HTTPLogger) Flush() error {
+	l.m.Lock()
+	if len(l.pending) > 0 {
+		l.enqueue()
+	}
+	for l.sending {
+		l.sent.Wait()
+	}
+	l.m.Unlock()
+	return l.takeError()
+}
+
+// enqueue queues the pending events as a batch, starting a goroutine to send the queue if there isn't one. The caller
+// must hold l.m.
+func (l *HTTPLogger) enqueue() {
+	l.queue = append(l.queue, l.pending)
+	l.pending = nil
+	if !l.sending {
+		l.sending = true
+		go l.sendQueue()
+	}
+}
+
+// sendQueue sends the queued batches in order, until the queue is empty.
+func (l *HTTPLogger) sendQueue() {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for len(l.queue) > 0 {
+		batch := l.queue[0]
+		l.queue[0] = nil
+		l.queue = l.queue[1:]
+
+		l.m.Unlock()
+		l.send(batch)
+		l.m.Lock()
+	}
+	l.sending = false
+	l.sent.Broadcast()
+}
+
+// Close sends any pending events. It is equivalent to Flush, but allows an HTTPLogger to be used as an io.Closer.
+func (l *HTTPLogger) Close() error {
+	return l.Flush()
+}
+
+// Failures returns the number of batches which were dropped because they couldn't be sent, even after retrying.
+func (l *HTTPLogger) Failures() uint64 {
+	return atomic.LoadUint64(&l.failures)
+}
+
+// Dropped returns the number of events which have been dropped because the queue was full.
+func (l *HTTPLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// send POSTs the batch, retrying if appropriate. It is only called by sendQueue.
+func (l *HTTPLogger) send(batch []Event) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		atomic.AddUint64(&l.failures, 1)
+		l.record(err)
+		return
+	}
+
+	backoff := l.backoff
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		if retry, err = l.post(body); err == nil || !retry || attempt >= l.maxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	if err != nil {
+		atomic.AddUint64(&l.failures, 1)
+	}
+	l.record(err)
+}
+
+// post makes a single request, and reports whether it's worth retrying if it failed.
+func (l *HTTPLogger) post(body []byte) (retry bool, err error) {
+	rsp, err := l.client.Post(l.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, rsp.Body)
+	rsp.Body.Close()
+
+	switch {
+	case rsp.StatusCode >= 500:
+		return true, fmt.Errorf("slog: %s responded %s", l.url, rsp.Status)
+	case rsp.StatusCode >= 300:
+		return false, fmt.Errorf("slog: %s responded %s", l.url, rsp.Status)
+	}
+	return false, nil
+}
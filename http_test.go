@@ -0,0 +1,188 @@
+package slog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingestServer records the batches POSTed to it, responding with the given statuses in turn (and 200 after they are
+// exhausted).
+type ingestServer struct {
+	*httptest.Server
+	m        sync.Mutex
+	statuses []int
+	requests int
+	batches  [][]Event
+}
+
+func newIngestServer(t *testing.T, statuses ...int) *ingestServer {
+	s := &ingestServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.m.Lock()
+		defer s.m.Unlock()
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		s.requests++
+		if len(s.statuses) > 0 {
+			status := s.statuses[0]
+			s.statuses = s.statuses[1:]
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+		}
+		batch := []Event{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		s.batches = append(s.batches, batch)
+	}))
+	return s
+}
+
+func (s *ingestServer) messages() [][]string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	result := make([][]string, len(s.batches))
+	for i, batch := range s.batches {
+		for _, e := range batch {
+			result[i] = append(result[i], e.Message)
+		}
+	}
+	return result
+}
+
+func TestHTTPLogger(t *testing.T) {
+	s := newIngestServer(t)
+	defer s.Close()
+	l := NewHTTPLogger(s.URL, WithHTTPBatchSize(2))
+	ctx := context.Background()
+
+	l.Log(Eventf(InfoSeverity, ctx, "a"))
+	assert.Empty(t, s.messages())
+	l.Log(Eventf(InfoSeverity, ctx, "b"), Eventf(InfoSeverity, ctx, "c"))
+	waitFor(t, func() bool {
+		return len(s.messages()) == 1
+	})
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, s.messages())
+
+	l.Log(Eventf(InfoSeverity, ctx, "d"))
+	require.NoError(t, l.Close())
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"d"}}, s.messages())
+	assert.NoError(t, l.LastError())
+	assert.Zero(t, l.Failures())
+}
+
+func TestHTTPLoggerRetries(t *testing.T) {
+	s := newIngestServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+	defer s.Close()
+	l := NewHTTPLogger(s.URL, WithHTTPRetries(2, time.Millisecond))
+
+	l.Log(Eventf(InfoSeverity, context.Background(), "a"))
+	require.NoError(t, l.Flush())
+	assert.Equal(t, [][]string{{"a"}}, s.messages())
+	assert.Equal(t, 3, s.requests)
+	assert.Zero(t, l.Failures())
+}
+
+func TestHTTPLoggerFailure(t *testing.T) {
+	s := newIngestServer(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer s.Close()
+	l := NewHTTPLogger(s.URL, WithHTTPRetries(1, time.Millisecond))
+
+	l.Log(Eventf(InfoSeverity, context.Background(), "a"))
+	assert.Error(t, l.Flush())
+	assert.Error(t, l.LastError())
+	assert.EqualValues(t, 1, l.Failures())
+	assert.Equal(t, 2, s.requests)
+
+	// A client error isn't retried
+	s.statuses = []int{http.StatusBadRequest}
+	l.Log(Eventf(InfoSeverity, context.Background(), "b"))
+	assert.Error(t, l.Flush())
+	assert.EqualValues(t, 2, l.Failures())
+	assert.Equal(t, 3, s.requests)
+
+	// The logger recovers once the endpoint does
+	l.Log(Eventf(InfoSeverity, context.Background(), "c"))
+	assert.NoError(t, l.Flush())
+	assert.NoError(t, l.LastError())
+	assert.Equal(t, [][]string{{"c"}}, s.messages())
+}
+
+func TestHTTPLoggerNetworkError(t *testing.T) {
+	s := newIngestServer(t)
+	s.Close()
+	l := NewHTTPLogger(s.URL, WithHTTPRetries(1, time.Millisecond))
+
+	l.Log(Eventf(InfoSeverity, context.Background(), "a"))
+	assert.Error(t, l.Flush())
+	assert.EqualValues(t, 1, l.Failures())
+}
+
+func TestHTTPLoggerSendsInBackground(t *testing.T) {
+	release := make(chan struct{})
+	s := newIngestServer(t)
+	defer s.Close()
+	handler := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler.ServeHTTP(w, r)
+	})
+	l := NewHTTPLogger(s.URL, WithHTTPBatchSize(1))
+
+	// Logging doesn't wait for the endpoint, and batches queued behind a slow request are sent in order
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			l.Log(Eventf(InfoSeverity, context.Background(), strconv.Itoa(i)))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Log waited for the endpoint")
+	}
+	assert.Empty(t, s.messages())
+
+	close(release)
+	require.NoError(t, l.Flush())
+	assert.Equal(t, [][]string{{"0"}, {"1"}, {"2"}, {"3"}, {"4"}}, s.messages())
+}
+
+func TestHTTPLoggerQueueSize(t *testing.T) {
+	release := make(chan struct{})
+	s := newIngestServer(t)
+	defer s.Close()
+	handler := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler.ServeHTTP(w, r)
+	})
+	l := NewHTTPLogger(s.URL, WithHTTPBatchSize(1), WithHTTPQueueSize(2))
+
+	// The first batch is taken off the queue to be sent, so two more can be queued behind it before any are dropped
+	l.Log(Eventf(InfoSeverity, context.Background(), "0"))
+	waitFor(t, func() bool {
+		l.m.Lock()
+		defer l.m.Unlock()
+		return len(l.queue) == 0
+	})
+	for i := 1; i < 5; i++ {
+		l.Log(Eventf(InfoSeverity, context.Background(), strconv.Itoa(i)))
+	}
+	assert.EqualValues(t, 2, l.Dropped())
+
+	close(release)
+	require.NoError(t, l.Flush())
+	assert.Equal(t, [][]string{{"0"}, {"1"}, {"2"}}, s.messages())
+	assert.Zero(t, l.Failures())
+}
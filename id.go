@@ -0,0 +1,75 @@
+package slog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	idGenerator  = NewID
+	idGeneratorM sync.RWMutex
+	// fallbackIDCounter disambiguates fallback IDs generated within the same nanosecond.
+	fallbackIDCounter uint64
+)
+
+// SetIDGenerator replaces the function used to generate event IDs. If the generator returns an error, events are
+// given a fallback ID derived from the current time instead.
+func SetIDGenerator(g func() (string, error)) {
+	idGeneratorM.Lock()
+	defer idGeneratorM.Unlock()
+	idGenerator = g
+}
+
+// ResetIDGenerator restores the default ID generator, which produces random (v4) UUIDs.
+func ResetIDGenerator() {
+	SetIDGenerator(NewID)
+}
+
+// NewID generates a random (v4) UUID, as described by RFC 4122, which is the default format of event IDs. It can be
+// used to generate other IDs in the same format, such as request IDs. UUIDs are generated here, rather than with a
+// UUID library, so that the package has no dependencies on its core path.
+func NewID() (string, error) {
+	var id [16]byte
+	if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+		return "", err
+	}
+	id[6] = id[6]&0x0f | 0x40 // Version 4
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	return formatUUID(&id), nil
+}
+
+// formatUUID formats the UUID in its canonical form, without the allocations of fmt.Sprintf, as an ID is generated
+// for every event.
+func formatUUID(id *[16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf[:])
+}
+
+// newEventID returns an ID for a new event. Unless strict mode is enabled, it never fails: if the configured generator
+// errors, an ID is built from the timestamp and a process-wide counter so that the event is not lost.
+func newEventID(ts time.Time) string {
+	idGeneratorM.RLock()
+	g := idGenerator
+	idGeneratorM.RUnlock()
+
+	id, err := g()
+	if err == nil {
+		return id
+	}
+	strictPanic(fmt.Errorf("generating event ID: %w", err))
+	return fmt.Sprintf("%d-%d", ts.UnixNano(), atomic.AddUint64(&fallbackIDCounter, 1))
+}
@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestFormatUUID(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var id [16]byte
+		_, err := rand.Read(id[:])
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), formatUUID(&id))
+	}
+}
+
+func TestNewID(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		id, err := NewID()
+		require.NoError(t, err)
+		assert.Regexp(t, uuidV4Pattern, id)
+	}
+}
+
+func TestEventIDsUniqueUnderConcurrency(t *testing.T) {
+	const goroutines, events = 16, 2000
+	ids := make([][]string, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < events; j++ {
+				ids[i] = append(ids[i], Eventf(InfoSeverity, context.Background(), "foo").Id)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, goroutines*events)
+	for _, goroutineIDs := range ids {
+		for _, id := range goroutineIDs {
+			require.False(t, seen[id], "ID %s assigned twice", id)
+			seen[id] = true
+		}
+	}
+	assert.Len(t, seen, goroutines*events)
+}
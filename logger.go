@@ -24,6 +24,26 @@ type FromErrorLogger interface {
 	FromError(ctx context.Context, msg string, err error, params ...interface{})
 }
 
+// LevelEnabler is a logger which can report whether events of a given severity would be logged, so that callers can
+// skip building them if not.
+type LevelEnabler interface {
+	Enabled(sev Severity) bool
+}
+
+// MinSeveritySetter is a logger whose minimum severity can be changed at runtime.
+type MinSeveritySetter interface {
+	SetMinSeverity(sev Severity)
+}
+
+// enabled reports whether l would log events of the given severity. Loggers which do not implement LevelEnabler are
+// assumed to log everything.
+func enabled(l Logger, sev Severity) bool {
+	if le, ok := l.(LevelEnabler); ok {
+		return le.Enabled(sev)
+	}
+	return true
+}
+
 // SeverityLogger is a logger which can log at different severity levels.
 type SeverityLogger struct {
 	Logger
@@ -4,9 +4,22 @@ import (
 	"sync"
 )
 
+// subscriptionBufferSize is the number of events buffered for each subscriber before further events are dropped.
+const subscriptionBufferSize = 100
+
 type InMemoryLogger struct {
 	*sync.Mutex
 	events EventSet
+	// max is the maximum number of events retained, or 0 if unbounded. Once full, events is a ring buffer whose oldest
+	// event is at index start.
+	max   int
+	start int
+	// onEvict is called with each event discarded from a full bounded logger.
+	onEvict func(Event)
+	// drainM serializes calls to DrainTo, so that drained events are forwarded in order.
+	drainM           sync.Mutex
+	subscribers      map[int]chan Event
+	nextSubscriberID int
 }
 
 // NewInMemoryLogger creates a logger that will keep all log events in memory
@@ -17,20 +30,138 @@ func NewInMemoryLogger() *InMemoryLogger {
 	}
 }
 
+// NewBoundedInMemoryLogger creates a logger that keeps only the most recent max events in memory, discarding the
+// oldest once it is full.
+func NewBoundedInMemoryLogger(max int) *InMemoryLogger {
+	l := NewInMemoryLogger()
+	if max > 0 {
+		l.max = max
+		l.events = make(EventSet, 0, max)
+	}
+	return l
+}
+
+// OnEvict sets a function which is called with each event discarded by a bounded logger (see
+// NewBoundedInMemoryLogger) to make room for a newer one, oldest first, so that it can be archived elsewhere rather
+// than lost. It is called outside the logger's lock, so it may log, even to this logger.
+func (l *InMemoryLogger) OnEvict(fn func(Event)) {
+	l.Lock()
+	defer l.Unlock()
+	l.onEvict = fn
+}
+
 func (l *InMemoryLogger) Log(evs ...Event) {
+	l.Lock()
+	var evicted []Event
+	for _, e := range evs {
+		if l.max > 0 && len(l.events) == l.max {
+			if l.onEvict != nil {
+				evicted = append(evicted, l.events[l.start])
+			}
+			l.events[l.start] = e
+			l.start = (l.start + 1) % l.max
+			continue
+		}
+		l.events = append(l.events, e)
+	}
+	for _, ch := range l.subscribers {
+		for _, e := range evs {
+			// Drop the event for slow subscribers rather than blocking
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+	}
+	onEvict := l.onEvict
+	l.Unlock()
+
+	for _, e := range evicted {
+		onEvict(e)
+	}
+}
+
+// Subscribe returns a channel which receives each event subsequently logged, and a function which ends the
+// subscription and closes the channel. Events are buffered for each subscriber; if a subscriber falls behind, events
+// are dropped for it rather than blocking Log.
+func (l *InMemoryLogger) Subscribe() (<-chan Event, func()) {
 	l.Lock()
 	defer l.Unlock()
-	l.events = append(l.events, evs...)
+	if l.subscribers == nil {
+		l.subscribers = map[int]chan Event{}
+	}
+	id := l.nextSubscriberID
+	l.nextSubscriberID++
+	ch := make(chan Event, subscriptionBufferSize)
+	l.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			l.Lock()
+			defer l.Unlock()
+			delete(l.subscribers, id)
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
 }
 
 func (l *InMemoryLogger) Flush() error {
 	return nil
 }
 
+// Reset discards all logged events.
+func (l *InMemoryLogger) Reset() {
+	l.Lock()
+	defer l.Unlock()
+	l.resetLocked()
+}
+
+// resetLocked discards all logged events. The caller must hold the lock.
+func (l *InMemoryLogger) resetLocked() {
+	if l.max > 0 {
+		l.events = l.events[:0]
+	} else {
+		l.events = nil
+	}
+	l.start = 0
+}
+
+// Len returns the number of logged events.
+func (l *InMemoryLogger) Len() int {
+	l.Lock()
+	defer l.Unlock()
+	return len(l.events)
+}
+
 func (l *InMemoryLogger) Events() EventSet {
 	l.Lock()
 	defer l.Unlock()
+	return l.eventsLocked()
+}
+
+// DrainTo forwards all logged events to next, in order, and discards them. Events logged concurrently are either
+// forwarded or kept for a later call, but never lost or forwarded twice. This supports buffering events during
+// startup until the real Logger is ready.
+func (l *InMemoryLogger) DrainTo(next Logger) {
+	l.drainM.Lock()
+	defer l.drainM.Unlock()
+
+	l.Lock()
+	evs := l.eventsLocked()
+	l.resetLocked()
+	l.Unlock()
+
+	if len(evs) > 0 {
+		next.Log(evs...)
+	}
+}
+
+// eventsLocked returns a copy of the logged events in chronological order. The caller must hold the lock.
+func (l *InMemoryLogger) eventsLocked() EventSet {
 	output := make(EventSet, len(l.events))
-	copy(output, l.events)
+	n := copy(output, l.events[l.start:])
+	copy(output[n:], l.events[:l.start])
 	return output
 }
@@ -0,0 +1,176 @@
+package slog
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryLoggerSubscribe(t *testing.T) {
+	logger := NewInMemoryLogger()
+	logger.Log(Eventf(InfoSeverity, context.Background(), "before"))
+
+	first, unsubscribeFirst := logger.Subscribe()
+	second, unsubscribeSecond := logger.Subscribe()
+	defer unsubscribeSecond()
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "after"))
+	for _, ch := range []<-chan Event{first, second} {
+		e := <-ch
+		assert.Equal(t, "after", e.Message)
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, ok := <-first
+	assert.False(t, ok)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "unsubscribed"))
+	e := <-second
+	assert.Equal(t, "unsubscribed", e.Message)
+}
+
+func TestInMemoryLoggerSubscribeSlowSubscriber(t *testing.T) {
+	logger := NewInMemoryLogger()
+	ch, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriptionBufferSize+10; i++ {
+		logger.Log(Eventf(InfoSeverity, context.Background(), "event"))
+	}
+
+	require.Equal(t, subscriptionBufferSize+10, logger.Len())
+	assert.Len(t, ch, subscriptionBufferSize)
+}
+
+func TestInMemoryLoggerLenAndReset(t *testing.T) {
+	logger := NewInMemoryLogger()
+	assert.Equal(t, 0, logger.Len())
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "1"),
+		Eventf(InfoSeverity, context.Background(), "2"))
+	logger.Log(Eventf(InfoSeverity, context.Background(), "3"))
+	assert.Equal(t, 3, logger.Len())
+
+	logger.Reset()
+	assert.Equal(t, 0, logger.Len())
+	assert.Empty(t, logger.Events())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "4"))
+	assert.Equal(t, 1, logger.Len())
+	assert.Equal(t, []string{"4"}, messages(logger.Events()))
+}
+
+func TestBoundedInMemoryLoggerLenAndReset(t *testing.T) {
+	logger := NewBoundedInMemoryLogger(2)
+	logger.Log(Eventf(InfoSeverity, context.Background(), "1"))
+	assert.Equal(t, 1, logger.Len())
+
+	// Eviction keeps the length at the bound
+	for i := 2; i <= 5; i++ {
+		logger.Log(Eventf(InfoSeverity, context.Background(), strconv.Itoa(i)))
+		assert.Equal(t, 2, logger.Len())
+	}
+	assert.Equal(t, []string{"4", "5"}, messages(logger.Events()))
+
+	// Reset clears the ring buffer mid-rotation, and it fills from empty again
+	logger.Reset()
+	assert.Equal(t, 0, logger.Len())
+	assert.Empty(t, logger.Events())
+	for i := 6; i <= 8; i++ {
+		logger.Log(Eventf(InfoSeverity, context.Background(), strconv.Itoa(i)))
+	}
+	assert.Equal(t, 2, logger.Len())
+	assert.Equal(t, []string{"7", "8"}, messages(logger.Events()))
+}
+
+func TestBoundedInMemoryLogger(t *testing.T) {
+	logger := NewBoundedInMemoryLogger(3)
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "1"),
+		Eventf(InfoSeverity, context.Background(), "2"))
+	assert.Equal(t, []string{"1", "2"}, messages(logger.Events()))
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "3"),
+		Eventf(InfoSeverity, context.Background(), "4"),
+		Eventf(InfoSeverity, context.Background(), "5"))
+	assert.Equal(t, []string{"3", "4", "5"}, messages(logger.Events()))
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "6"))
+	assert.Equal(t, []string{"4", "5", "6"}, messages(logger.Events()))
+	assert.Equal(t, 3, logger.Len())
+
+	logger.Reset()
+	assert.Empty(t, logger.Events())
+	logger.Log(Eventf(InfoSeverity, context.Background(), "7"))
+	assert.Equal(t, []string{"7"}, messages(logger.Events()))
+}
+
+func TestBoundedInMemoryLoggerOnEvict(t *testing.T) {
+	logger := NewBoundedInMemoryLogger(2)
+	archive := NewInMemoryLogger()
+	logger.OnEvict(func(e Event) {
+		archive.Log(e)
+		// The callback runs outside the lock, so can use the evicting logger
+		logger.Len()
+	})
+
+	for i := 1; i <= 3; i++ {
+		logger.Log(Eventf(InfoSeverity, context.Background(), strconv.Itoa(i)))
+	}
+	assert.Equal(t, []string{"1"}, messages(archive.Events()))
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "4"),
+		Eventf(InfoSeverity, context.Background(), "5"),
+		Eventf(InfoSeverity, context.Background(), "6"))
+	assert.Equal(t, []string{"1", "2", "3", "4"}, messages(archive.Events()))
+	assert.Equal(t, []string{"5", "6"}, messages(logger.Events()))
+
+	// An unbounded logger never evicts
+	unbounded := NewInMemoryLogger()
+	unbounded.OnEvict(func(e Event) {
+		t.Error("unexpected eviction")
+	})
+	unbounded.Log(Eventf(InfoSeverity, context.Background(), "1"), Eventf(InfoSeverity, context.Background(), "2"))
+}
+
+func messages(evs EventSet) []string {
+	result := make([]string, len(evs))
+	for i, e := range evs {
+		result[i] = e.Message
+	}
+	return result
+}
+
+func TestInMemoryLoggerDrainTo(t *testing.T) {
+	buffer := NewInMemoryLogger()
+	buffer.Log(
+		Eventf(InfoSeverity, context.Background(), "1"),
+		Eventf(InfoSeverity, context.Background(), "2"))
+
+	next := NewInMemoryLogger()
+	buffer.DrainTo(next)
+	assert.Equal(t, []string{"1", "2"}, messages(next.Events()))
+	assert.Equal(t, 0, buffer.Len())
+
+	// Events logged concurrently with draining are each forwarded exactly once
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			buffer.Log(Eventf(InfoSeverity, context.Background(), "concurrent"))
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		buffer.DrainTo(next)
+	}
+	wg.Wait()
+	buffer.DrainTo(next)
+	assert.Equal(t, 102, next.Len())
+}
@@ -1,6 +1,15 @@
 package slog
 
+import (
+	"sync"
+)
+
 // A MultiLogger sends invocations to multiple Loggers.
+//
+// Each call to Log forwards the events to each sub-logger in turn, so every sub-logger receives the events of a
+// single call in the order given. MultiLogger adds no synchronisation of its own: concurrent calls to Log from
+// different goroutines may interleave, so sub-loggers may observe the events of different calls in different
+// relative orders. Use an OrderedMultiLogger if a total order is needed.
 type MultiLogger []Logger
 
 // Log the event to each sub-logger.
@@ -19,3 +28,31 @@ func (ls MultiLogger) Flush() error {
 	}
 	return nil
 }
+
+// An OrderedMultiLogger is a MultiLogger which serializes calls to Log, so that every sub-logger observes all events
+// in the same total order, even when logged concurrently.
+type OrderedMultiLogger struct {
+	m  sync.Mutex
+	ls MultiLogger
+}
+
+// NewOrderedMultiLogger creates an OrderedMultiLogger which sends invocations to the given Loggers.
+func NewOrderedMultiLogger(ls ...Logger) *OrderedMultiLogger {
+	return &OrderedMultiLogger{
+		ls: ls,
+	}
+}
+
+// Log the events to each sub-logger, after any concurrent calls have completed.
+func (l *OrderedMultiLogger) Log(evs ...Event) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.ls.Log(evs...)
+}
+
+// Flush all sub-loggers.
+func (l *OrderedMultiLogger) Flush() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.ls.Flush()
+}
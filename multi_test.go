@@ -0,0 +1,46 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiLoggerPreservesOrder(t *testing.T) {
+	first, second := NewInMemoryLogger(), NewInMemoryLogger()
+	logger := MultiLogger{first, second}
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "one"),
+		Eventf(InfoSeverity, context.Background(), "two"))
+
+	assert.Equal(t, []string{"one", "two"}, messages(first.Events()))
+	assert.Equal(t, []string{"one", "two"}, messages(second.Events()))
+}
+
+func TestOrderedMultiLogger(t *testing.T) {
+	first, second := NewInMemoryLogger(), NewInMemoryLogger()
+	logger := NewOrderedMultiLogger(first, second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Log(
+					Eventf(InfoSeverity, context.Background(), fmt.Sprintf("%d-%d-a", i, j)),
+					Eventf(InfoSeverity, context.Background(), fmt.Sprintf("%d-%d-b", i, j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(t, logger.Flush())
+
+	require.Equal(t, 1000, first.Len())
+	assert.Equal(t, messages(first.Events()), messages(second.Events()))
+}
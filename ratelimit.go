@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"sync"
+	"time"
+)
+
+// A GlobalRateLimitLogger forwards at most a fixed rate of events to another Logger, regardless of their severity,
+// to protect sinks with a hard limit on throughput, such as a metered vendor. It is a token bucket: up to burst events
+// can be forwarded at once, and the bucket refills at perSecond events per second. Events over the limit are dropped
+// and counted. CriticalSeverity events always pass, without using a token, unless configured otherwise with
+// WithCriticalBypass.
+type GlobalRateLimitLogger struct {
+	next           Logger
+	perSecond      float64
+	burst          float64
+	criticalBypass bool
+
+	m          sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	dropped    uint64
+}
+
+// A GlobalRateLimitOption configures a GlobalRateLimitLogger.
+type GlobalRateLimitOption func(*GlobalRateLimitLogger)
+
+// WithCriticalBypass sets whether CriticalSeverity events are forwarded regardless of the limit. By default they are.
+func WithCriticalBypass(bypass bool) GlobalRateLimitOption {
+	return func(l *GlobalRateLimitLogger) {
+		l.criticalBypass = bypass
+	}
+}
+
+// NewGlobalRateLimitLogger creates a GlobalRateLimitLogger which forwards up to perSecond events per second to next,
+// with bursts of up to burst events. The bucket starts full. A burst less than 1 is treated as 1.
+func NewGlobalRateLimitLogger(next Logger, perSecond int, burst int, opts ...GlobalRateLimitOption) *GlobalRateLimitLogger {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &GlobalRateLimitLogger{
+		next:           next,
+		perSecond:      float64(perSecond),
+		burst:          float64(burst),
+		criticalBypass: true,
+		tokens:         float64(burst),
+		lastRefill:     now(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log forwards the events which are within the limit, and drops the rest.
+func (l *GlobalRateLimitLogger) Log(evs ...Event) {
+	allowed := make([]Event, 0, len(evs))
+	l.m.Lock()
+	l.refillLocked()
+	for _, e := range evs {
+		switch {
+		case l.criticalBypass && e.Severity >= CriticalSeverity:
+		case l.tokens >= 1:
+			l.tokens--
+		default:
+			l.dropped++
+			continue
+		}
+		allowed = append(allowed, e)
+	}
+	l.m.Unlock()
+
+	if len(allowed) > 0 {
+		l.next.Log(allowed...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *GlobalRateLimitLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// Enabled reports whether the underlying logger would log events of the given severity.
+func (l *GlobalRateLimitLogger) Enabled(sev Severity) bool {
+	return enabled(l.next, sev)
+}
+
+// Dropped returns the number of events which have been dropped for exceeding the limit.
+func (l *GlobalRateLimitLogger) Dropped() uint64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.dropped
+}
+
+// refillLocked adds the tokens accrued since the last refill. The caller must hold the lock.
+func (l *GlobalRateLimitLogger) refillLocked() {
+	t := now()
+	if elapsed := t.Sub(l.lastRefill); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.perSecond
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.lastRefill = t
+}
@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalRateLimitLogger(t *testing.T) {
+	current := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return current
+	})
+	defer ResetClock()
+
+	next := NewInMemoryLogger()
+	logger := NewGlobalRateLimitLogger(next, 10, 5)
+	ctx := context.Background()
+
+	// A burst is capped across all severities, but critical events always pass
+	burst := make([]Event, 0, 20)
+	for i := 0; i < 10; i++ {
+		burst = append(burst, Eventf(InfoSeverity, ctx, "info"), Eventf(ErrorSeverity, ctx, "error"))
+	}
+	logger.Log(burst...)
+	logger.Log(Eventf(CriticalSeverity, ctx, "critical"))
+	assert.Equal(t, 6, next.Len())
+	assert.Equal(t, uint64(15), logger.Dropped())
+	assert.Equal(t, "critical", next.Events()[5].Message)
+
+	// The bucket refills at the configured rate, up to the burst size
+	current = current.Add(200 * time.Millisecond)
+	logger.Log(burst...)
+	assert.Equal(t, 8, next.Len())
+	current = current.Add(time.Hour)
+	logger.Log(burst...)
+	assert.Equal(t, 13, next.Len())
+	assert.Equal(t, uint64(48), logger.Dropped())
+}
+
+func TestGlobalRateLimitLoggerWithoutCriticalBypass(t *testing.T) {
+	current := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return current
+	})
+	defer ResetClock()
+
+	next := NewInMemoryLogger()
+	logger := NewGlobalRateLimitLogger(next, 1, 1, WithCriticalBypass(false))
+	ctx := context.Background()
+
+	logger.Log(Eventf(CriticalSeverity, ctx, "one"), Eventf(CriticalSeverity, ctx, "two"))
+	assert.Equal(t, []string{"one"}, messages(next.Events()))
+	assert.Equal(t, uint64(1), logger.Dropped())
+}
@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// A RotatingFileLogger writes each event on its own line to a file, rotating it once it reaches a maximum size.
+type RotatingFileLogger struct {
+	writeErrors
+	m          sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// NewRotatingFileLogger creates a RotatingFileLogger which writes to the file at path, appending to it if it exists.
+// When writing an event would take the file beyond maxBytes, it is rotated: the file is renamed to path.1, any
+// existing path.1 to path.2 and so on, and backups beyond maxBackups are deleted. A single event larger than maxBytes
+// is still written, to its own file.
+func NewRotatingFileLogger(path string, maxBytes int64, maxBackups int) (*RotatingFileLogger, error) {
+	l := &RotatingFileLogger{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Log writes the events to the file, rotating it as needed. Any error is reported by the next call to Flush.
+func (l *RotatingFileLogger) Log(evs ...Event) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, e := range evs {
+		line := e.String() + "\n"
+		if l.f == nil || (l.size > 0 && l.size+int64(len(line)) > l.maxBytes) {
+			if err := l.rotate(); err != nil {
+				l.record(err)
+				continue
+			}
+		}
+		n, err := l.f.WriteString(line)
+		l.size += int64(n)
+		l.record(err)
+	}
+}
+
+// Flush syncs the file to disk, and returns the first error encountered since the last Flush.
+func (l *RotatingFileLogger) Flush() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.f != nil {
+		l.record(l.f.Sync())
+	}
+	return l.takeError()
+}
+
+// Close closes the file. Events logged after Close cause the file to be reopened.
+func (l *RotatingFileLogger) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// open opens the file for appending. The caller must hold l.m, or have exclusive access to l.
+func (l *RotatingFileLogger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the current file and its backups along, and opens a new file. If the file isn't open (because it was
+// closed, or a previous rotation failed), it is reopened without rotating. The caller must hold l.m.
+func (l *RotatingFileLogger) rotate() error {
+	if l.f == nil {
+		return l.open()
+	}
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	l.f = nil
+
+	if l.maxBackups <= 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+
+	if err := os.Remove(l.backupPath(l.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func (l *RotatingFileLogger) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
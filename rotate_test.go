@@ -0,0 +1,80 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "slog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.log")
+
+	line := Eventf(InfoSeverity, context.Background(), "event0").String() + "\n"
+	// Each file holds two events
+	logger, err := NewRotatingFileLogger(path, int64(2*len(line)), 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 7; i++ {
+		logger.Log(Eventf(InfoSeverity, context.Background(), fmt.Sprintf("event%d", i)))
+	}
+	require.NoError(t, logger.Flush())
+	require.NoError(t, logger.Close())
+
+	contents := func(p string) string {
+		b, err := ioutil.ReadFile(p)
+		require.NoError(t, err)
+		return string(b)
+	}
+	assert.Contains(t, contents(path), "event6")
+	assert.Contains(t, contents(path+".1"), "event4")
+	assert.Contains(t, contents(path+".1"), "event5")
+	assert.Contains(t, contents(path+".2"), "event2")
+	assert.Contains(t, contents(path+".2"), "event3")
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileLoggerConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "slog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.log")
+
+	logger, err := NewRotatingFileLogger(path, 1024, 100)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				logger.Log(Eventf(InfoSeverity, context.Background(), "event"))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, logger.Flush())
+
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	lines := 0
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+		lines += strings.Count(string(b), "\n")
+	}
+	assert.Equal(t, 200, lines)
+}
@@ -0,0 +1,89 @@
+package slog
+
+import (
+	"reflect"
+)
+
+// A SeverityRouterLogger dispatches each event to a Logger chosen by the event's severity.
+type SeverityRouterLogger struct {
+	// loggers contains each distinct underlying logger once; routes and fallback are indices into it.
+	loggers  []Logger
+	routes   map[Severity]int
+	fallback int
+}
+
+// NewSeverityRouterLogger creates a SeverityRouterLogger which sends events to the Logger registered for their
+// severity in routes, or to fallback if there is none. If fallback is nil, unrouted events are dropped.
+func NewSeverityRouterLogger(routes map[Severity]Logger, fallback Logger) *SeverityRouterLogger {
+	r := &SeverityRouterLogger{
+		routes:   make(map[Severity]int, len(routes)),
+		fallback: -1,
+	}
+	for sev, l := range routes {
+		if l != nil {
+			r.routes[sev] = r.indexOf(l)
+		}
+	}
+	if fallback != nil {
+		r.fallback = r.indexOf(fallback)
+	}
+	return r
+}
+
+// indexOf returns the index of l in r.loggers, adding it if it is not already present.
+func (r *SeverityRouterLogger) indexOf(l Logger) int {
+	// Only loggers whose types are always safe to compare (such as pointers) are deduplicated. Structs may be
+	// comparable but hold values which aren't, such as a MultiLogger in an interface field, and comparing them would
+	// panic.
+	if isHashable(reflect.TypeOf(l)) {
+		for i, existing := range r.loggers {
+			if isHashable(reflect.TypeOf(existing)) && existing == l {
+				return i
+			}
+		}
+	}
+	r.loggers = append(r.loggers, l)
+	return len(r.loggers) - 1
+}
+
+// Log dispatches each event to the logger for its severity. Events bound for the same logger are sent together, in
+// their original order.
+func (r *SeverityRouterLogger) Log(evs ...Event) {
+	batches := make([][]Event, len(r.loggers))
+	for _, e := range evs {
+		i, ok := r.routes[e.Severity]
+		if !ok {
+			i = r.fallback
+		}
+		if i < 0 {
+			continue
+		}
+		batches[i] = append(batches[i], e)
+	}
+	for i, batch := range batches {
+		if len(batch) > 0 {
+			r.loggers[i].Log(batch...)
+		}
+	}
+}
+
+// Enabled reports whether events of the given severity are routed to a logger which would log them.
+func (r *SeverityRouterLogger) Enabled(sev Severity) bool {
+	i, ok := r.routes[sev]
+	if !ok {
+		i = r.fallback
+	}
+	return i >= 0 && enabled(r.loggers[i], sev)
+}
+
+// Flush each distinct underlying logger once. All loggers are flushed even if one fails, and the first error is
+// returned.
+func (r *SeverityRouterLogger) Flush() error {
+	var firstErr error
+	for _, l := range r.loggers {
+		if err := l.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,83 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingFlushLogger struct {
+	*InMemoryLogger
+	flushes int
+}
+
+func (l *countingFlushLogger) Flush() error {
+	l.flushes++
+	return nil
+}
+
+func TestSeverityRouterLogger(t *testing.T) {
+	errors := &countingFlushLogger{InMemoryLogger: NewInMemoryLogger()}
+	fallback := &countingFlushLogger{InMemoryLogger: NewInMemoryLogger()}
+	logger := NewSeverityRouterLogger(map[Severity]Logger{
+		ErrorSeverity:    errors,
+		CriticalSeverity: errors,
+	}, fallback)
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "info"),
+		Eventf(ErrorSeverity, context.Background(), "error"),
+		Eventf(CriticalSeverity, context.Background(), "critical"),
+		Eventf(DebugSeverity, context.Background(), "debug"))
+
+	if assert.Len(t, errors.Events(), 2) {
+		assert.Equal(t, "error", errors.Events()[0].Message)
+		assert.Equal(t, "critical", errors.Events()[1].Message)
+	}
+	if assert.Len(t, fallback.Events(), 2) {
+		assert.Equal(t, "info", fallback.Events()[0].Message)
+		assert.Equal(t, "debug", fallback.Events()[1].Message)
+	}
+
+	assert.NoError(t, logger.Flush())
+	assert.Equal(t, 1, errors.flushes)
+	assert.Equal(t, 1, fallback.flushes)
+}
+
+func TestSeverityRouterLoggerUncomparableFields(t *testing.T) {
+	// SeverityLogger is a comparable struct, but comparing these would panic as their MultiLoggers aren't comparable
+	errors := SeverityLogger{Logger: MultiLogger{NewInMemoryLogger()}}
+	fallback := SeverityLogger{Logger: MultiLogger{NewInMemoryLogger()}}
+	var logger *SeverityRouterLogger
+	assert.NotPanics(t, func() {
+		logger = NewSeverityRouterLogger(map[Severity]Logger{
+			ErrorSeverity:    errors,
+			CriticalSeverity: errors,
+		}, fallback)
+	})
+	logger.Log(Eventf(ErrorSeverity, context.Background(), "error"))
+	assert.NoError(t, logger.Flush())
+}
+
+func TestSeverityRouterLoggerNoFallback(t *testing.T) {
+	errors := NewInMemoryLogger()
+	logger := NewSeverityRouterLogger(map[Severity]Logger{
+		ErrorSeverity: errors,
+	}, nil)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "info"))
+	assert.Empty(t, errors.Events())
+	assert.NoError(t, logger.Flush())
+}
+
+func TestSeverityRouterLoggerEnabled(t *testing.T) {
+	logger := NewSeverityRouterLogger(map[Severity]Logger{
+		ErrorSeverity: NewInMemoryLogger(),
+		WarnSeverity:  NewLevelFilterLogger(NewInMemoryLogger(), ErrorSeverity),
+	}, nil)
+
+	assert.True(t, logger.Enabled(ErrorSeverity))
+	assert.False(t, logger.Enabled(WarnSeverity))
+	assert.False(t, logger.Enabled(InfoSeverity))
+}
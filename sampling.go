@@ -0,0 +1,82 @@
+package slog
+
+import (
+	"context"
+)
+
+type samplingKey struct{}
+
+// WithSampling returns a copy of the parent context carrying a head sampling decision, which is honoured by a
+// SamplingLogger for every event logged with it. This lets the edge of a system decide the verbosity of a whole
+// request tree.
+func WithSampling(ctx context.Context, sampled bool) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, samplingKey{}, sampled)
+}
+
+// Sampled returns the sampling decision stored in the context, if any.
+func Sampled(ctx context.Context) (sampled bool, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+	sampled, ok = ctx.Value(samplingKey{}).(bool)
+	return sampled, ok
+}
+
+// A SamplingLogger forwards events to another Logger according to the sampling decision in their context: all events
+// from sampled contexts are forwarded, but only events of at least ErrorSeverity from unsampled ones. Events whose
+// context carries no decision are treated according to the logger's default.
+//
+// A SamplingLogger only ever drops events, so it composes with a LevelFilterLogger rather than overriding it: an event
+// must pass both to be logged. To log everything for a sampled request behind a LevelFilterLogger, its threshold must
+// also be lowered with WithMinSeverity.
+type SamplingLogger struct {
+	next           Logger
+	sampledDefault bool
+}
+
+// NewSamplingLogger creates a SamplingLogger which forwards events to next. Events whose context has no sampling
+// decision are treated as sampled if sampledByDefault is true.
+func NewSamplingLogger(next Logger, sampledByDefault bool) *SamplingLogger {
+	return &SamplingLogger{
+		next:           next,
+		sampledDefault: sampledByDefault,
+	}
+}
+
+// Log forwards the events which are sampled, or severe enough to be logged regardless.
+func (l *SamplingLogger) Log(evs ...Event) {
+	filtered := make([]Event, 0, len(evs))
+	for _, e := range evs {
+		if l.sampled(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) > 0 {
+		l.next.Log(filtered...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *SamplingLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// Enabled reports whether the underlying logger would log events of the given severity. As the sampling decision is
+// only known from an event's context, this assumes the event is sampled.
+func (l *SamplingLogger) Enabled(sev Severity) bool {
+	return enabled(l.next, sev)
+}
+
+func (l *SamplingLogger) sampled(e Event) bool {
+	if e.Severity >= ErrorSeverity {
+		return true
+	}
+	sampled, ok := Sampled(e.Context)
+	if !ok {
+		sampled = l.sampledDefault
+	}
+	return sampled
+}
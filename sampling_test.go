@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewSamplingLogger(next, true)
+
+	sampled := WithSampling(context.Background(), true)
+	unsampled := WithSampling(context.Background(), false)
+	logger.Log(
+		Eventf(DebugSeverity, sampled, "sampled debug"),
+		Eventf(InfoSeverity, unsampled, "unsampled info"),
+		Eventf(ErrorSeverity, unsampled, "unsampled error"),
+		Eventf(InfoSeverity, context.Background(), "default info"))
+
+	events := next.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "sampled debug", events[0].Message)
+	assert.Equal(t, "unsampled error", events[1].Message)
+	assert.Equal(t, "default info", events[2].Message)
+}
+
+func TestSamplingLoggerUnsampledByDefault(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewSamplingLogger(next, false)
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "default info"),
+		Eventf(CriticalSeverity, context.Background(), "default critical"))
+
+	events := next.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "default critical", events[0].Message)
+}
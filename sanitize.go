@@ -0,0 +1,69 @@
+package slog
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// sanitizeMessages is non-zero if Eventf should escape control characters in messages and metadata.
+var sanitizeMessages int32
+
+// SetSanitizeMessages enables or disables escaping newlines and other control characters in event messages and
+// string metadata values, so that user-controlled data interpolated into an event can't forge additional lines in a
+// line-based sink. Newlines, carriage returns and tabs are escaped as \n, \r and \t, and other control characters as
+// \x or \u escapes, as in Go string literals. This is disabled by default, as it changes the message even for sinks
+// (like JSON) which aren't vulnerable.
+func SetSanitizeMessages(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&sanitizeMessages, v)
+}
+
+func sanitizeMessagesEnabled() bool {
+	return atomic.LoadInt32(&sanitizeMessages) != 0
+}
+
+// sanitizeMetadata escapes control characters in the string values of the metadata, which is modified in place.
+func sanitizeMetadata(metadata map[string]interface{}) {
+	for k, v := range metadata {
+		if s, ok := v.(string); ok {
+			metadata[k] = sanitize(s)
+		}
+	}
+}
+
+// sanitize returns s with control characters escaped. If it contains none, s is returned without allocating.
+func sanitize(s string) string {
+	if strings.IndexFunc(s, unicode.IsControl) < 0 {
+		return s
+	}
+
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x80 && unicode.IsControl(r):
+			b.WriteString(`\x`)
+			b.WriteByte(hex[r>>4])
+			b.WriteByte(hex[r&0xf])
+		case unicode.IsControl(r):
+			b.WriteString(`\u`)
+			for shift := 12; shift >= 0; shift -= 4 {
+				b.WriteByte(hex[(r>>uint(shift))&0xf])
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
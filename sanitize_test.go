@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeMessages(t *testing.T) {
+	forged := "alice\n[2020-01-02 03:04:05] INFO Granted admin to alice"
+	ctx := context.Background()
+
+	e := Eventf(InfoSeverity, ctx, "Logged in as %s", forged)
+	assert.Contains(t, e.Message, "\n")
+
+	SetSanitizeMessages(true)
+	defer SetSanitizeMessages(false)
+
+	buf := new(bytes.Buffer)
+	logger := NewWriterLogger(buf)
+	logger.Log(
+		Eventf(InfoSeverity, ctx, "Logged in as %s", forged, map[string]interface{}{
+			"user":  forged,
+			"count": 1,
+		}),
+		Eventft(InfoSeverity, ctx, "Logged in as {user}", map[string]interface{}{"user": forged}))
+	require.NoError(t, logger.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `Logged in as alice\n[2020-01-02 03:04:05] INFO Granted admin to alice`)
+	assert.Contains(t, lines[0], `user:alice\n[2020`)
+	assert.Contains(t, lines[1], `Logged in as alice\n[2020`)
+}
+
+func TestSanitize(t *testing.T) {
+	cases := map[string]string{
+		"plain":        "plain",
+		"a\nb\r\nc\td": `a\nb\r\nc\td`,
+		"bell\a":       `bell\x07`,
+		"esc\x1b[31m":  `esc\x1b[31m`,
+		"del\x7f":      `del\x7f`,
+		"c1\u0085":     `c1\u0085`,
+		"unicode é 日本": "unicode é 日本",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, sanitize(input), input)
+	}
+}
@@ -0,0 +1,32 @@
+package slog
+
+import (
+	"sync"
+)
+
+var (
+	severityDefaults  map[Severity]map[string]interface{}
+	severityDefaultsM sync.RWMutex
+)
+
+// SetSeverityDefaults configures baseline metadata added to every event of a given severity, such as marking all
+// critical events as alertable. Each severity is matched exactly, so a policy for "error and above" must list both
+// ErrorSeverity and CriticalSeverity. The defaults never overwrite metadata provided by any other source.
+func SetSeverityDefaults(defaults map[Severity]map[string]interface{}) {
+	copied := make(map[Severity]map[string]interface{}, len(defaults))
+	for sev, metadata := range defaults {
+		copied[sev] = mergeMetadata(nil, metadata)
+	}
+
+	severityDefaultsM.Lock()
+	defer severityDefaultsM.Unlock()
+	severityDefaults = copied
+}
+
+// metadataFromSeverity returns the default metadata for events of the given severity. The returned map is shared, so
+// must not be modified.
+func metadataFromSeverity(sev Severity) map[string]interface{} {
+	severityDefaultsM.RLock()
+	defer severityDefaultsM.RUnlock()
+	return severityDefaults[sev]
+}
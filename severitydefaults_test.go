@@ -0,0 +1,30 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityDefaults(t *testing.T) {
+	SetSeverityDefaults(map[Severity]map[string]interface{}{
+		ErrorSeverity:    {"alertable": true},
+		CriticalSeverity: {"alertable": true, "page": true},
+	})
+	defer SetSeverityDefaults(nil)
+
+	e := Eventf(ErrorSeverity, context.Background(), "error")
+	assert.Equal(t, map[string]interface{}{"alertable": true}, e.Metadata)
+
+	e = Eventf(InfoSeverity, context.Background(), "info")
+	assert.Nil(t, e.Metadata)
+
+	ctx := WithParams(context.Background(), map[string]string{"page": "context"})
+	e = Eventf(CriticalSeverity, ctx, "critical", map[string]interface{}{"alertable": false})
+	assert.Equal(t, map[string]interface{}{"alertable": false, "page": "context"}, e.Metadata)
+
+	// The defaults themselves aren't modified by events
+	e = Eventf(CriticalSeverity, context.Background(), "critical")
+	assert.Equal(t, map[string]interface{}{"alertable": true, "page": true}, e.Metadata)
+}
@@ -0,0 +1,56 @@
+package sloghttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/monzo/slog"
+)
+
+const (
+	// MethodMetadataKey is the metadata key for the method of a logged request.
+	MethodMetadataKey = "http_method"
+	// PathMetadataKey is the metadata key for the URL path of a logged request.
+	PathMetadataKey = "http_path"
+	// StatusMetadataKey is the metadata key for the response status of a logged request.
+	StatusMetadataKey = "http_status"
+)
+
+// LogRequest logs a handled request, with its method, path, response status and latency (under
+// slog.DurationMetadataKey, in milliseconds) as metadata, for standard access logging. Requests which failed with a
+// 5xx status are logged as errors, those with a 4xx status as warnings, and any others as info. As with the
+// package-level logging functions of slog, the request is logged to the Logger carried by the context (see
+// slog.WithLogger), or otherwise the default Logger.
+func LogRequest(ctx context.Context, r *http.Request, status int, dur time.Duration) {
+	path := ""
+	if r.URL != nil {
+		path = r.URL.Path
+	}
+
+	logf := slog.Info
+	switch StatusSeverity(status) {
+	case slog.ErrorSeverity:
+		logf = slog.Error
+	case slog.WarnSeverity:
+		logf = slog.Warn
+	}
+	logf(ctx, "%s %s %d", r.Method, path, status, map[string]interface{}{
+		MethodMetadataKey:        r.Method,
+		PathMetadataKey:          path,
+		StatusMetadataKey:        status,
+		slog.DurationMetadataKey: dur.Milliseconds(),
+	})
+}
+
+// StatusSeverity returns the severity with which a request with the given response status is logged by LogRequest.
+func StatusSeverity(status int) slog.Severity {
+	switch {
+	case status >= 500:
+		return slog.ErrorSeverity
+	case status >= 400:
+		return slog.WarnSeverity
+	default:
+		return slog.InfoSeverity
+	}
+}
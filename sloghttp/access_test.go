@@ -0,0 +1,70 @@
+package sloghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/monzo/slog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRequest(t *testing.T) {
+	logger := slog.NewInMemoryLogger()
+	oldLogger := slog.DefaultLogger()
+	slog.SetDefaultLogger(logger)
+	defer slog.SetDefaultLogger(oldLogger)
+
+	testCases := []struct {
+		status   int
+		expected slog.Severity
+	}{
+		{http.StatusOK, slog.InfoSeverity},
+		{http.StatusMovedPermanently, slog.InfoSeverity},
+		{http.StatusNotFound, slog.WarnSeverity},
+		{http.StatusTooManyRequests, slog.WarnSeverity},
+		{http.StatusInternalServerError, slog.ErrorSeverity},
+		{http.StatusServiceUnavailable, slog.ErrorSeverity},
+	}
+	for _, tC := range testCases {
+		t.Run(http.StatusText(tC.status), func(t *testing.T) {
+			logger.Reset()
+			req := httptest.NewRequest(http.MethodPost, "/widgets/w1?colour=red", nil)
+			ctx := slog.WithParams(context.Background(), map[string]string{DefaultParamKey: "r1"})
+			LogRequest(ctx, req, tC.status, 1500*time.Microsecond)
+
+			events := logger.Events()
+			require.Len(t, events, 1)
+			e := events[0]
+			assert.Equal(t, tC.expected, e.Severity)
+			assert.Equal(t, map[string]interface{}{
+				MethodMetadataKey:        http.MethodPost,
+				PathMetadataKey:          "/widgets/w1",
+				StatusMetadataKey:        tC.status,
+				slog.DurationMetadataKey: int64(1),
+				DefaultParamKey:          "r1",
+			}, e.Metadata)
+			assert.Contains(t, e.Message, "POST /widgets/w1")
+		})
+	}
+}
+
+func TestLogRequestContextLogger(t *testing.T) {
+	defaultLogger := slog.NewInMemoryLogger()
+	oldLogger := slog.DefaultLogger()
+	slog.SetDefaultLogger(defaultLogger)
+	defer slog.SetDefaultLogger(oldLogger)
+
+	requestLogger := slog.NewInMemoryLogger()
+	ctx := slog.WithLogger(context.Background(), requestLogger)
+	LogRequest(ctx, httptest.NewRequest(http.MethodGet, "/widgets", nil), http.StatusNotFound, time.Millisecond)
+
+	assert.Empty(t, defaultLogger.Events())
+	events := requestLogger.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, slog.WarnSeverity, events[0].Severity)
+	assert.Equal(t, "GET /widgets 404", events[0].Message)
+}
@@ -0,0 +1,90 @@
+// Package sloghttp provides HTTP middleware which adds request details to slog params, and access logging.
+package sloghttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/monzo/slog"
+)
+
+const (
+	// DefaultHeader is the header from which the request ID is read, and to which it is written.
+	DefaultHeader = "X-Request-Id"
+	// DefaultParamKey is the slog param key under which the request ID is stored.
+	DefaultParamKey = "request_id"
+
+	// maxRequestIDLength is the length of the longest request ID which is accepted from a request's header.
+	maxRequestIDLength = 128
+	// requestIDPunctuation is the punctuation, besides ASCII letters and digits, allowed in a request ID from a
+	// request's header. It covers UUIDs and base64 as well as most tracing formats.
+	requestIDPunctuation = "-_.:+/="
+)
+
+// A RequestID configures middleware which propagates a request ID into slog params, so that all events logged while
+// handling a request can be correlated.
+type RequestID struct {
+	// Header is the header from which the request ID is read, and to which it is written. If empty, DefaultHeader is
+	// used.
+	Header string
+	// ParamKey is the slog param key under which the request ID is stored. If empty, DefaultParamKey is used.
+	ParamKey string
+}
+
+// Middleware propagates request IDs using the default header and param key. See RequestID.Middleware.
+func Middleware(next http.Handler) http.Handler {
+	return RequestID{}.Middleware(next)
+}
+
+// Middleware returns a handler which reads the request ID from the request's header, generating one if it is absent,
+// and adds it to the params of the request's context before calling next. The ID is also set on the response header.
+// As the header comes from the client, an ID which is longer than 128 bytes, or has characters other than ASCII
+// letters, digits and "-_.:+/=", is replaced with a generated one, so that it can't forge or bloat log output.
+func (r RequestID) Middleware(next http.Handler) http.Handler {
+	header, paramKey := r.Header, r.ParamKey
+	if header == "" {
+		header = DefaultHeader
+	}
+	if paramKey == "" {
+		paramKey = DefaultParamKey
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(header)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		if id != "" {
+			w.Header().Set(header, id)
+			req = req.WithContext(slog.WithParams(req.Context(), map[string]string{paramKey: id}))
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// newRequestID returns a random (v4) UUID, or an empty string if one couldn't be generated.
+func newRequestID() string {
+	id, err := slog.NewID()
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// validRequestID reports whether id, from a request's header, is non-empty and made up only of allowed characters,
+// without being too long.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte(requestIDPunctuation, c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package sloghttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/monzo/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	var params map[string]string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params = slog.Params(req.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultHeader, "r1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, map[string]string{DefaultParamKey: "r1"}, params)
+	assert.Equal(t, "r1", rec.Header().Get(DefaultHeader))
+}
+
+func TestMiddlewareGeneratesID(t *testing.T) {
+	var params map[string]string
+	handler := RequestID{
+		Header:   "X-Trace",
+		ParamKey: "trace",
+	}.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params = slog.Params(req.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	id := rec.Header().Get("X-Trace")
+	assert.NotEmpty(t, id)
+	assert.Equal(t, map[string]string{"trace": id}, params)
+}
+
+func TestMiddlewareReplacesInvalidID(t *testing.T) {
+	for _, id := range []string{
+		"r1\nlevel=critical",
+		"r1 r2",
+		`"r1"`,
+		"r\u00e9",
+		strings.Repeat("a", maxRequestIDLength+1),
+	} {
+		var params map[string]string
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			params = slog.Params(req.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(DefaultHeader, id)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		generated := rec.Header().Get(DefaultHeader)
+		assert.NotEqual(t, id, generated, id)
+		assert.True(t, validRequestID(generated), generated)
+		assert.Equal(t, map[string]string{DefaultParamKey: generated}, params, id)
+	}
+
+	// IDs in common formats are kept
+	for _, id := range []string{
+		"0f8fad5b-d9cb-469f-a165-70867728950e",
+		"dGVzdA+/==",
+		"Root=1-5759e988-bd862e3fe1be46a994272793",
+		strings.Repeat("a", maxRequestIDLength),
+	} {
+		assert.True(t, validRequestID(id), id)
+	}
+}
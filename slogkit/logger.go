@@ -0,0 +1,84 @@
+// Package slogkit provides an adapter which satisfies go-kit's log.Logger interface, so that code written against
+// go-kit can emit slog events. As go-kit's interface is a single method, it is satisfied without importing go-kit.
+package slogkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/monzo/slog"
+)
+
+const (
+	// MessageKey is the key whose value is used as the event's message.
+	MessageKey = "msg"
+	// LevelKey is the key whose value is used as the event's severity, if it is the name of one.
+	LevelKey = "level"
+)
+
+// ErrMissingValue is used as the value of the final key when Log is given an odd number of keyvals, as by go-kit.
+var ErrMissingValue = errors.New("(MISSING)")
+
+// A GoKitLogger implements go-kit's log.Logger interface by converting each call to Log into a slog event, and
+// sending it to a slog Logger.
+type GoKitLogger struct {
+	next slog.Logger
+	sev  slog.Severity
+}
+
+// NewGoKitLogger creates a GoKitLogger which sends events to next. Events whose keyvals don't include a recognised
+// level have severity sev.
+func NewGoKitLogger(next slog.Logger, sev slog.Severity) *GoKitLogger {
+	return &GoKitLogger{
+		next: next,
+		sev:  sev,
+	}
+}
+
+// Log converts the alternating keys and values into an event. The value of MessageKey becomes its message, and the
+// value of LevelKey its severity, if it is the name of one (as go-kit's levels are); the other keyvals become its
+// metadata. The first value which is an error is also used as the event's Error. Log never returns an error.
+func (l *GoKitLogger) Log(keyvals ...interface{}) error {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, ErrMissingValue)
+	}
+
+	sev, msg := l.sev, ""
+	var err error
+	metadata := make(map[string]interface{}, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		k, v := fmt.Sprint(keyvals[i]), keyvals[i+1]
+		switch k {
+		case MessageKey:
+			msg = fmt.Sprint(v)
+			continue
+		case LevelKey:
+			if parsed, parseErr := slog.ParseSeverity(fmt.Sprint(v)); parseErr == nil {
+				sev = parsed
+				continue
+			}
+		}
+		if vErr, ok := v.(error); ok && err == nil && vErr != ErrMissingValue {
+			err = vErr
+		}
+		metadata[k] = v
+	}
+
+	// The message is passed without params, so that it isn't treated as a format string
+	e := slog.Eventf(sev, context.Background(), msg)
+	// Metadata slog added to the event (such as severity defaults) is kept, unless a keyval overrides it
+	if len(metadata) > 0 {
+		for k, v := range e.Metadata {
+			if _, ok := metadata[k]; !ok {
+				metadata[k] = v
+			}
+		}
+		e.Metadata = metadata
+	}
+	if err != nil {
+		e.Error = err
+	}
+	l.next.Log(e)
+	return nil
+}
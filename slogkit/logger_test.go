@@ -0,0 +1,72 @@
+package slogkit
+
+import (
+	"testing"
+
+	"github.com/monzo/slog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goKitLogger is go-kit's log.Logger interface.
+type goKitLogger interface {
+	Log(keyvals ...interface{}) error
+}
+
+var _ goKitLogger = &GoKitLogger{}
+
+// level behaves like one of go-kit's level values.
+type level string
+
+func (l level) String() string {
+	return string(l)
+}
+
+func TestGoKitLogger(t *testing.T) {
+	logger := slog.NewInMemoryLogger()
+	l := NewGoKitLogger(logger, slog.InfoSeverity)
+
+	require.NoError(t, l.Log("msg", "loading %s widget", "count", 42))
+	require.NoError(t, l.Log(LevelKey, level("warn"), MessageKey, "slow", "err", assert.AnError))
+	require.NoError(t, l.Log(LevelKey, "verbose", "transport", "http", "method"))
+
+	events := logger.Events()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, slog.InfoSeverity, events[0].Severity)
+	assert.Equal(t, "loading %s widget", events[0].Message)
+	assert.Equal(t, map[string]interface{}{"count": 42}, events[0].Metadata)
+	assert.Nil(t, events[0].Error)
+
+	assert.Equal(t, slog.WarnSeverity, events[1].Severity)
+	assert.Equal(t, "slow", events[1].Message)
+	assert.Equal(t, assert.AnError, events[1].Error)
+	assert.Equal(t, map[string]interface{}{"err": assert.AnError}, events[1].Metadata)
+
+	// An unrecognised level is kept as metadata, and a missing value is marked as such
+	assert.Equal(t, slog.InfoSeverity, events[2].Severity)
+	assert.Equal(t, "", events[2].Message)
+	assert.Nil(t, events[2].Error)
+	assert.Equal(t, map[string]interface{}{
+		"level":     "verbose",
+		"transport": "http",
+		"method":    ErrMissingValue,
+	}, events[2].Metadata)
+}
+
+func TestGoKitLoggerKeepsSlogMetadata(t *testing.T) {
+	slog.SetSeverityDefaults(map[slog.Severity]map[string]interface{}{
+		slog.InfoSeverity: {"team": "payments", "count": 0},
+	})
+	defer slog.SetSeverityDefaults(nil)
+	logger := slog.NewInMemoryLogger()
+	l := NewGoKitLogger(logger, slog.InfoSeverity)
+
+	require.NoError(t, l.Log("msg", "loading", "count", 42))
+	events := logger.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, map[string]interface{}{
+		"team":  "payments",
+		"count": 42,
+	}, events[0].Metadata)
+}
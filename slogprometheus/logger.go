@@ -0,0 +1,50 @@
+// Package slogprometheus provides a Logger which counts events in Prometheus metrics.
+package slogprometheus
+
+import (
+	"github.com/monzo/slog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SeverityLabel is the name of the label by which MetricsLogger partitions its counter.
+const SeverityLabel = "severity"
+
+// A MetricsLogger counts events by severity before forwarding them to another Logger.
+type MetricsLogger struct {
+	next    slog.Logger
+	counter *prometheus.CounterVec
+}
+
+// NewMetricsLogger creates a MetricsLogger which increments counter for each event, before forwarding it to next. The
+// counter must have a single label, SeverityLabel. If counter is nil, events are forwarded without being counted.
+//
+// Events are deliberately only labelled by severity, to keep the cardinality of the metric low.
+func NewMetricsLogger(next slog.Logger, counter *prometheus.CounterVec) *MetricsLogger {
+	return &MetricsLogger{
+		next:    next,
+		counter: counter,
+	}
+}
+
+// NewCounterVec creates a CounterVec with the labels expected by MetricsLogger.
+func NewCounterVec(opts prometheus.CounterOpts) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(opts, []string{SeverityLabel})
+}
+
+// Log counts the events and forwards them to the underlying logger.
+func (l *MetricsLogger) Log(evs ...slog.Event) {
+	if l.counter != nil {
+		for _, e := range evs {
+			// A counter with the wrong labels shouldn't prevent events being logged, so errors are ignored
+			if c, err := l.counter.GetMetricWithLabelValues(e.Severity.String()); err == nil {
+				c.Inc()
+			}
+		}
+	}
+	l.next.Log(evs...)
+}
+
+// Flush the underlying logger.
+func (l *MetricsLogger) Flush() error {
+	return l.next.Flush()
+}
@@ -0,0 +1,36 @@
+package slogprometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/monzo/slog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsLogger(t *testing.T) {
+	next := slog.NewInMemoryLogger()
+	counter := NewCounterVec(prometheus.CounterOpts{
+		Name: "log_events_total",
+	})
+	logger := NewMetricsLogger(next, counter)
+
+	logger.Log(
+		slog.Eventf(slog.InfoSeverity, context.Background(), "one"),
+		slog.Eventf(slog.ErrorSeverity, context.Background(), "two"),
+		slog.Eventf(slog.ErrorSeverity, context.Background(), "three"))
+
+	assert.Len(t, next.Events(), 3)
+	assert.Equal(t, float64(1), testutil.ToFloat64(counter.WithLabelValues("INFO")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(counter.WithLabelValues("ERROR")))
+}
+
+func TestMetricsLoggerNilCounter(t *testing.T) {
+	next := slog.NewInMemoryLogger()
+	logger := NewMetricsLogger(next, nil)
+
+	logger.Log(slog.Eventf(slog.InfoSeverity, context.Background(), "one"))
+	assert.Len(t, next.Events(), 1)
+}
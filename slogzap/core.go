@@ -0,0 +1,113 @@
+// Package slogzap provides a zapcore.Core which sends zap log entries to a slog Logger, so that code written against
+// zap can emit slog events.
+package slogzap
+
+import (
+	"context"
+
+	"github.com/monzo/slog"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerNameMetadataKey is the metadata key for the name of the zap logger which wrote an entry.
+const LoggerNameMetadataKey = "logger"
+
+// Core is a zapcore.Core which converts entries into slog events and sends them to a slog Logger.
+type Core struct {
+	zapcore.LevelEnabler
+	logger slog.Logger
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = &Core{}
+
+// NewCore creates a Core which sends entries enabled by enab to logger.
+func NewCore(logger slog.Logger, enab zapcore.LevelEnabler) *Core {
+	return &Core{
+		LevelEnabler: enab,
+		logger:       logger,
+	}
+}
+
+// With returns a copy of the core with the given fields added to every entry.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return &clone
+}
+
+// Check adds the core to the checked entry if its level is enabled.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write converts the entry and fields into a slog event and logs it. Fields become metadata, and the first error
+// field is also used as the event's Error.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var err error
+	for _, fs := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fs {
+			f.AddTo(enc)
+			if fieldErr, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType && err == nil {
+				err = fieldErr
+			}
+		}
+	}
+	if ent.LoggerName != "" {
+		enc.Fields[LoggerNameMetadataKey] = ent.LoggerName
+	}
+
+	// The message is passed without params, so that it isn't treated as a format string
+	e := slog.EventfAt(ent.Time, Severity(ent.Level), context.Background(), ent.Message)
+	e.Metadata = mergeMetadata(e.Metadata, enc.Fields)
+	if err != nil {
+		e.Error = err
+	}
+	c.logger.Log(e)
+	return nil
+}
+
+// mergeMetadata returns the metadata slog added to an event (such as severity defaults), overlaid with the fields.
+func mergeMetadata(metadata, fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return metadata
+	}
+	merged := make(map[string]interface{}, len(metadata)+len(fields))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Sync flushes the underlying logger.
+func (c *Core) Sync() error {
+	return c.logger.Flush()
+}
+
+// Severity returns the slog severity corresponding to a zap level. Levels more severe than Error map to
+// CriticalSeverity, and levels less severe than Debug map to TraceSeverity.
+func Severity(l zapcore.Level) slog.Severity {
+	switch {
+	case l < zapcore.DebugLevel:
+		return slog.TraceSeverity
+	case l == zapcore.DebugLevel:
+		return slog.DebugSeverity
+	case l == zapcore.InfoLevel:
+		return slog.InfoSeverity
+	case l == zapcore.WarnLevel:
+		return slog.WarnSeverity
+	case l == zapcore.ErrorLevel:
+		return slog.ErrorSeverity
+	default:
+		return slog.CriticalSeverity
+	}
+}
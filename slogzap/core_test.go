@@ -0,0 +1,79 @@
+package slogzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/monzo/slog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	logger := slog.NewInMemoryLogger()
+	z := zap.New(NewCore(logger, zapcore.InfoLevel)).Named("payments").With(zap.String("service", "payments"))
+
+	z.Debug("not enabled")
+	z.Info("loading %s widget", zap.Int("count", 42))
+	z.Error("failed", zap.Error(assert.AnError))
+
+	events := logger.Events()
+	require.Len(t, events, 2)
+
+	assert.Equal(t, slog.InfoSeverity, events[0].Severity)
+	assert.Equal(t, "loading %s widget", events[0].Message)
+	assert.Equal(t, map[string]interface{}{
+		"service": "payments",
+		"count":   int64(42),
+		"logger":  "payments",
+	}, events[0].Metadata)
+	assert.Nil(t, events[0].Error)
+
+	assert.Equal(t, slog.ErrorSeverity, events[1].Severity)
+	assert.Equal(t, assert.AnError, events[1].Error)
+	assert.Equal(t, assert.AnError.Error(), events[1].Metadata["error"])
+}
+
+func TestCoreTimestamp(t *testing.T) {
+	slog.SetTimezone(time.FixedZone("EST", -5*60*60))
+	defer slog.SetTimezone(nil)
+	logger := slog.NewInMemoryLogger()
+	core := NewCore(logger, zapcore.InfoLevel)
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("BST", 60*60))
+	require.NoError(t, core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Time: ts, Message: "timed"}, nil))
+
+	events := logger.Events()
+	require.Len(t, events, 1)
+	assert.True(t, ts.Equal(events[0].Timestamp))
+	assert.Equal(t, "EST", events[0].Timestamp.Location().String())
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, slog.TraceSeverity, Severity(zapcore.DebugLevel-1))
+	assert.Equal(t, slog.DebugSeverity, Severity(zapcore.DebugLevel))
+	assert.Equal(t, slog.InfoSeverity, Severity(zapcore.InfoLevel))
+	assert.Equal(t, slog.WarnSeverity, Severity(zapcore.WarnLevel))
+	assert.Equal(t, slog.ErrorSeverity, Severity(zapcore.ErrorLevel))
+	assert.Equal(t, slog.CriticalSeverity, Severity(zapcore.DPanicLevel))
+	assert.Equal(t, slog.CriticalSeverity, Severity(zapcore.FatalLevel))
+}
+
+func TestCoreKeepsSlogMetadata(t *testing.T) {
+	slog.SetSeverityDefaults(map[slog.Severity]map[string]interface{}{
+		slog.InfoSeverity: {"team": "payments", "count": 0},
+	})
+	defer slog.SetSeverityDefaults(nil)
+	logger := slog.NewInMemoryLogger()
+	z := zap.New(NewCore(logger, zapcore.InfoLevel))
+
+	z.Info("loading", zap.Int("count", 42))
+	events := logger.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, map[string]interface{}{
+		"team":  "payments",
+		"count": int64(42),
+	}, events[0].Metadata)
+}
@@ -0,0 +1,13 @@
+module github.com/monzo/slog/slogzap
+
+go 1.13
+
+require (
+	github.com/monzo/slog v0.0.0-20261016010500-4cb6b92769f7
+	github.com/stretchr/testify v1.4.0
+	go.uber.org/zap v1.16.0
+)
+
+// The replace directive only applies when developing in this repository, so that changes to slog and the adapter can
+// be made together; consumers get the version required above.
+replace github.com/monzo/slog => ../
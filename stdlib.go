@@ -1,7 +1,12 @@
 package slog
 
 import (
+	"bytes"
+	"context"
 	"log"
+	"os"
+	"strings"
+	"sync"
 )
 
 // StdlibLogger is a very simple logger which forwards events to Go's standard library logger
@@ -16,3 +21,98 @@ func (s StdlibLogger) Log(evs ...Event) {
 func (s StdlibLogger) Flush() error {
 	return nil
 }
+
+// A LogWriter is an io.Writer which logs each line written to it as an event.
+type LogWriter struct {
+	m   sync.Mutex
+	sev Severity
+	buf []byte
+}
+
+// NewLogWriter returns a LogWriter which sends each line written to it as an event of the given severity via the
+// default Logger. This can be used to capture the output of libraries which use the standard library's log package:
+//
+//	log.SetOutput(slog.NewLogWriter(slog.InfoSeverity))
+//
+// Lines may be split across writes; a line is logged once its terminating newline is written, or when the LogWriter
+// is flushed or closed. If the default Logger is a StdlibLogger, as it is unless SetDefaultLogger is called, the
+// events are written to stderr in the same format, rather than back into the standard library's logger. Other Loggers
+// must not write to the standard library's logger, or each line would never be logged.
+func NewLogWriter(sev Severity) *LogWriter {
+	return &LogWriter{
+		sev: sev,
+	}
+}
+
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	w.buf = append(w.buf, p...)
+	var lines []string
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, strings.TrimSuffix(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	w.m.Unlock()
+
+	w.log(lines)
+	return len(p), nil
+}
+
+// Flush logs any partial line which has been written without its terminating newline.
+func (w *LogWriter) Flush() error {
+	w.m.Lock()
+	var lines []string
+	if len(w.buf) > 0 {
+		lines = append(lines, strings.TrimSuffix(string(w.buf), "\r"))
+		w.buf = nil
+	}
+	w.m.Unlock()
+
+	w.log(lines)
+	return nil
+}
+
+// Close flushes any partial line. The LogWriter can still be written to afterwards.
+func (w *LogWriter) Close() error {
+	return w.Flush()
+}
+
+func (w *LogWriter) log(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	l := DefaultLogger()
+	if l == nil {
+		return
+	}
+	if _, ok := l.(StdlibLogger); ok {
+		// The standard library's logger is (probably) writing to this LogWriter, and holds its lock while it does
+		l = stderrLogger{}
+	}
+
+	// The lines are passed without params, so they aren't treated as format strings
+	evs := make([]Event, len(lines))
+	for i, line := range lines {
+		evs[i] = Eventf(w.sev, context.Background(), line)
+	}
+	logAndNotify(l, evs...)
+}
+
+// stderrLogger logs events to stderr in the same format as StdlibLogger with the standard library's default settings,
+// but without going through the standard library's logger.
+type stderrLogger struct{}
+
+func (s stderrLogger) Log(evs ...Event) {
+	l := log.New(os.Stderr, "", log.LstdFlags)
+	for _, e := range evs {
+		l.Print(e.String())
+	}
+}
+
+func (s stderrLogger) Flush() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogWriter(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	w := NewLogWriter(WarnSeverity)
+	io.WriteString(w, "first line\nsecond ")
+	io.WriteString(w, "line 100%\r\nunterminated")
+
+	events := logger.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "first line", events[0].Message)
+	assert.Equal(t, "second line 100%", events[1].Message)
+	assert.Equal(t, WarnSeverity, events[1].Severity)
+
+	stdlib := log.New(w, "", 0)
+	stdlib.Print("unterminated and from stdlib")
+	events = logger.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "unterminatedunterminated and from stdlib", events[2].Message)
+}
+
+func TestLogWriterFlush(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	w := NewLogWriter(InfoSeverity)
+	require.NoError(t, w.Flush())
+	assert.Empty(t, logger.Events())
+
+	io.WriteString(w, "complete\npartial")
+	require.NoError(t, w.Flush())
+	io.WriteString(w, "trailing\r")
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	assert.Equal(t, []string{"complete", "partial", "trailing"}, messages(logger.Events()))
+}
+
+// TestLogWriterWithStdlibLogger uses a LogWriter as the standard library logger's output while the default Logger
+// is StdlibLogger, which must not send the events back through the LogWriter.
+func TestLogWriterWithStdlibLogger(t *testing.T) {
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(StdlibLogger{})
+	defer SetDefaultLogger(oldLogger)
+
+	stderr, err := ioutil.TempFile("", "slog-stderr")
+	require.NoError(t, err)
+	defer os.Remove(stderr.Name())
+	defer stderr.Close()
+	oldStderr := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = oldStderr }()
+
+	oldOutput := log.Writer()
+	log.SetOutput(NewLogWriter(InfoSeverity))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Print("from stdlib")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		// The standard library's logger can't be restored, as it's still locked
+		t.Fatal("logging through the LogWriter didn't return")
+	}
+	log.SetOutput(oldOutput)
+
+	os.Stderr = oldStderr
+	out, err := ioutil.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(out), "from stdlib"))
+}
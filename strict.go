@@ -0,0 +1,31 @@
+package slog
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// strictMode is non-zero if Eventf should panic on problems constructing events.
+var strictMode int32
+
+// SetStrictMode enables or disables strict mode. When enabled, Eventf panics rather than doing its best with a
+// malformed event: if its format string expects more operands than it was given, or if an event ID can't be
+// generated. This is intended to catch logging bugs in development and tests, and is disabled by default.
+func SetStrictMode(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&strictMode, v)
+}
+
+func strictModeEnabled() bool {
+	return atomic.LoadInt32(&strictMode) != 0
+}
+
+// strictPanic panics with err if strict mode is enabled.
+func strictPanic(err error) {
+	if strictModeEnabled() {
+		panic(fmt.Errorf("slog: %w", err))
+	}
+}
@@ -0,0 +1,52 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictModeFormatMismatch(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Eventf(InfoSeverity, context.Background(), "foo %s %s", "bar")
+	})
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	assert.PanicsWithValue(t, `slog: format "foo %s %s" expects 2 operands but only 1 params were given`, func() {
+		defer rethrowAsString()
+		Eventf(InfoSeverity, context.Background(), "foo %s %s", "bar")
+	})
+	assert.NotPanics(t, func() {
+		Eventf(InfoSeverity, context.Background(), "foo %s", "bar", map[string]interface{}{"meta": "data"})
+	})
+}
+
+func TestStrictModeIDGeneratorFailure(t *testing.T) {
+	SetIDGenerator(func() (string, error) {
+		return "", errors.New("no entropy")
+	})
+	defer ResetIDGenerator()
+
+	assert.NotPanics(t, func() {
+		Eventf(InfoSeverity, context.Background(), "foo")
+	})
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	assert.PanicsWithValue(t, "slog: generating event ID: no entropy", func() {
+		defer rethrowAsString()
+		Eventf(InfoSeverity, context.Background(), "foo")
+	})
+}
+
+// rethrowAsString recovers a panic with an error, and panics again with its message so it can be compared.
+func rethrowAsString() {
+	if r := recover(); r != nil {
+		panic(r.(error).Error())
+	}
+}
@@ -0,0 +1,61 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// TemplateMissingMetadataKey is the metadata key listing the placeholders in a template which had no value.
+const TemplateMissingMetadataKey = "template_missing"
+
+var placeholderRe = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Eventft constructs an event from a message template containing named placeholders, such as "user {user_id} did
+// {action}". Each placeholder is replaced by the value of the event's metadata with that key, which includes both the
+// given metadata and any params in the context.
+//
+// The event's Message is the rendered template and its OriginalMessage is the template itself. Placeholders with no
+// value are left as-is, and listed in the event's metadata under TemplateMissingMetadataKey.
+func Eventft(sev Severity, ctx context.Context, template string, metadata map[string]interface{}) Event {
+	return eventf(sev, ctx, template, nil, eventOptions{explicit: true, metadata: metadata, template: true})
+}
+
+// renderTemplate replaces each placeholder in template with the value of the metadata with that key, returning the
+// keys of any placeholders with no value.
+func renderTemplate(template string, metadata map[string]interface{}) (string, []string) {
+	var missing []string
+	seen := map[string]bool{}
+	msg := placeholderRe.ReplaceAllStringFunc(template, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		if v, ok := metadata[key]; ok {
+			return fmt.Sprint(v)
+		}
+		if !seen[key] {
+			seen[key] = true
+			missing = append(missing, key)
+		}
+		return placeholder
+	})
+	return msg, missing
+}
+
+// Criticalt logs a templated event with critical severity via the default Logger (or one carried by the context). See Eventft.
+func Criticalt(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(CriticalSeverity, ctx, template, metadata))
+}
+
+// Errort logs a templated event with error severity via the default Logger (or one carried by the context). See Eventft.
+func Errort(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(ErrorSeverity, ctx, template, metadata))
+}
+
+// Warnt logs a templated event with warn severity via the default Logger (or one carried by the context). See Eventft.
+func Warnt(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(WarnSeverity, ctx, template, metadata))
+}
+
+// Infot logs a templated event with info severity via the default Logger (or one carried by the context). See Eventft.
+func Infot(ctx context.Context, template string, metadata map[string]interface{}) {
+	logTo(ctx, Eventft(InfoSeverity, ctx, template, metadata))
+}
@@ -0,0 +1,66 @@
+package slog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventft(t *testing.T) {
+	ctx := WithParams(context.Background(), map[string]string{"request_id": "r1"})
+	e := Eventft(InfoSeverity, ctx, "user {user_id} did {action} in {request_id} (100%)", map[string]interface{}{
+		"user_id": 42,
+		"action":  "login",
+	})
+
+	assert.Equal(t, "user 42 did login in r1 (100%)", e.Message)
+	assert.Equal(t, "user {user_id} did {action} in {request_id} (100%)", e.OriginalMessage)
+	assert.NotContains(t, e.Metadata, TemplateMissingMetadataKey)
+}
+
+func TestEventftMissingPlaceholders(t *testing.T) {
+	e := Eventft(InfoSeverity, context.Background(), "{who} did {action} to {who}", map[string]interface{}{
+		"action": "login",
+	})
+
+	assert.Equal(t, "{who} did login to {who}", e.Message)
+	assert.Equal(t, []string{"who"}, e.Metadata[TemplateMissingMetadataKey])
+}
+
+func TestInfot(t *testing.T) {
+	logger := NewInMemoryLogger()
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(oldLogger)
+
+	Infot(context.Background(), "loaded {count} widgets", map[string]interface{}{"count": 3})
+
+	events := logger.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, InfoSeverity, events[0].Severity)
+	assert.Equal(t, "loaded 3 widgets", events[0].Message)
+}
+
+func TestEventftOnSeverity(t *testing.T) {
+	oldLogger := DefaultLogger()
+	SetDefaultLogger(NewInMemoryLogger())
+	defer SetDefaultLogger(oldLogger)
+
+	called := make(chan Event, 1)
+	unregister := OnSeverity(CriticalSeverity, func(e Event) {
+		called <- e
+	})
+	defer unregister()
+
+	Criticalt(context.Background(), "lost {count} widgets", map[string]interface{}{"count": 3})
+	select {
+	case e := <-called:
+		assert.Equal(t, "lost 3 widgets", e.Message)
+		assert.Equal(t, "lost {count} widgets", e.OriginalMessage)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+}
@@ -0,0 +1,37 @@
+package slog
+
+import (
+	"time"
+)
+
+// DurationMetadataKey is the metadata key used by Timer.Field.
+const DurationMetadataKey = "duration_ms"
+
+// A Timer measures the duration of an operation, for logging as structured metadata.
+//
+//	t := slog.StartTimer()
+//	...
+//	slog.Info(ctx, "Done", t.Field())
+type Timer struct {
+	start time.Time
+}
+
+// StartTimer returns a Timer which started now.
+func StartTimer() Timer {
+	return Timer{
+		start: now(),
+	}
+}
+
+// Elapsed returns the time since the timer was started.
+func (t Timer) Elapsed() time.Duration {
+	return now().Sub(t.start)
+}
+
+// Field returns metadata containing the number of milliseconds since the timer was started. It can be called
+// repeatedly to record intermediate checkpoints.
+func (t Timer) Field() map[string]interface{} {
+	return map[string]interface{}{
+		DurationMetadataKey: t.Elapsed().Milliseconds(),
+	}
+}
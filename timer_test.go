@@ -0,0 +1,29 @@
+package slog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimer(t *testing.T) {
+	current := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time {
+		return current
+	})
+	defer ResetClock()
+
+	timer := StartTimer()
+	current = current.Add(42 * time.Millisecond)
+	assert.Equal(t, map[string]interface{}{
+		"duration_ms": int64(42),
+	}, timer.Field())
+
+	current = current.Add(time.Second)
+	e := Eventf(InfoSeverity, context.Background(), "done", timer.Field())
+	assert.Equal(t, map[string]interface{}{
+		"duration_ms": int64(1042),
+	}, e.Metadata)
+}
@@ -0,0 +1,132 @@
+package slog
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// TruncatedMetadataKey is added to the metadata of events modified by a TruncateLogger.
+	TruncatedMetadataKey = "_truncated"
+	truncatedSuffix      = "...(truncated)"
+)
+
+// A TruncateLogger limits the size of events before forwarding them to another Logger, so that a single oversized
+// value can't cause the whole event to be rejected downstream.
+type TruncateLogger struct {
+	next            Logger
+	maxValueBytes   int
+	maxKeys         int
+	maxMessageBytes int
+}
+
+// A TruncateOption configures a TruncateLogger.
+type TruncateOption func(*TruncateLogger)
+
+// WithMaxMessageBytes makes a TruncateLogger truncate messages longer than n bytes. By default, messages are limited
+// to the maximum value length; zero or less disables it.
+func WithMaxMessageBytes(n int) TruncateOption {
+	return func(l *TruncateLogger) {
+		l.maxMessageBytes = n
+	}
+}
+
+// NewTruncateLogger creates a TruncateLogger which truncates string metadata values longer than maxValueBytes, and
+// drops metadata keys beyond the first maxKeys in sorted order. Events which are modified have TruncatedMetadataKey
+// set to true. A limit of zero or less disables it.
+func NewTruncateLogger(next Logger, maxValueBytes, maxKeys int, opts ...TruncateOption) *TruncateLogger {
+	l := &TruncateLogger{
+		next:            next,
+		maxValueBytes:   maxValueBytes,
+		maxKeys:         maxKeys,
+		maxMessageBytes: maxValueBytes,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log truncates the events and forwards them to the underlying logger.
+func (l *TruncateLogger) Log(evs ...Event) {
+	truncated := make([]Event, len(evs))
+	for i, e := range evs {
+		truncated[i] = l.truncate(e)
+	}
+	l.next.Log(truncated...)
+}
+
+// Flush the underlying logger.
+func (l *TruncateLogger) Flush() error {
+	return l.next.Flush()
+}
+
+func (l *TruncateLogger) truncate(e Event) Event {
+	modified := false
+	if msg, ok := truncateString(e.Message, l.maxMessageBytes); ok {
+		e.Message = msg
+		modified = true
+	}
+	if !modified && !l.truncatesMetadata(e.Metadata) {
+		return e
+	}
+
+	keys := make([]string, 0, len(e.Metadata))
+	for k := range e.Metadata {
+		keys = append(keys, k)
+	}
+	if l.maxKeys > 0 && len(keys) > l.maxKeys {
+		sort.Strings(keys)
+		keys = keys[:l.maxKeys]
+		modified = true
+	}
+
+	// Build a new map rather than modifying the event's, as it may be shared with other loggers
+	metadata := make(map[string]interface{}, len(keys)+1)
+	for _, k := range keys {
+		v := e.Metadata[k]
+		if s, ok := v.(string); ok {
+			if truncated, ok := truncateString(s, l.maxValueBytes); ok {
+				v = truncated
+				modified = true
+			}
+		}
+		metadata[k] = v
+	}
+
+	if modified {
+		metadata[TruncatedMetadataKey] = true
+		e.Metadata = metadata
+	}
+	return e
+}
+
+// truncatesMetadata reports whether any of the metadata would be truncated or dropped, so that events which are
+// within the limits can be forwarded without copying their metadata.
+func (l *TruncateLogger) truncatesMetadata(metadata map[string]interface{}) bool {
+	if l.maxKeys > 0 && len(metadata) > l.maxKeys {
+		return true
+	}
+	if l.maxValueBytes <= 0 {
+		return false
+	}
+	for _, v := range metadata {
+		if s, ok := v.(string); ok && len(s) > l.maxValueBytes {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateString shortens s to at most maxBytes bytes (plus a suffix), without splitting a UTF-8 sequence. It reports
+// whether s was truncated.
+func truncateString(s string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s, false
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end] + truncatedSuffix, true
+}
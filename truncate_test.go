@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateLogger(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewTruncateLogger(next, 8, 2)
+
+	original := map[string]interface{}{
+		"a": strings.Repeat("x", 20),
+		"b": 42,
+		"c": "dropped",
+	}
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "a very long message", original),
+		Eventf(InfoSeverity, context.Background(), "short", map[string]interface{}{
+			"a": "fine",
+		}))
+
+	events := next.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "a very l...(truncated)", events[0].Message)
+	assert.Equal(t, map[string]interface{}{
+		"a":          "xxxxxxxx...(truncated)",
+		"b":          42,
+		"_truncated": true,
+	}, events[0].Metadata)
+	assert.Equal(t, "short", events[1].Message)
+	assert.Equal(t, map[string]interface{}{
+		"a": "fine",
+	}, events[1].Metadata)
+
+	// The original metadata should not have been modified
+	assert.Len(t, original, 3)
+}
+
+func TestTruncateLoggerMaxMessageBytes(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewTruncateLogger(next, 4, 0, WithMaxMessageBytes(8))
+
+	logger.Log(
+		Eventf(InfoSeverity, context.Background(), "a very long message"),
+		Eventf(InfoSeverity, context.Background(), "short", map[string]interface{}{"a": "fine"}))
+
+	events := next.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "a very l...(truncated)", events[0].Message)
+	assert.Equal(t, map[string]interface{}{"_truncated": true}, events[0].Metadata)
+
+	assert.Equal(t, "short", events[1].Message)
+	assert.Equal(t, map[string]interface{}{"a": "fine"}, events[1].Metadata)
+
+	// An event within the limits is forwarded without copying its metadata
+	assert.Zero(t, testing.AllocsPerRun(10, func() {
+		logger.truncate(events[1])
+	}))
+}
+
+func TestTruncateString(t *testing.T) {
+	// "é" is two bytes, so truncating at 2 bytes would split it
+	s, ok := truncateString("aéb", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "a...(truncated)", s)
+
+	s, ok = truncateString("abc", 3)
+	assert.False(t, ok)
+	assert.Equal(t, "abc", s)
+
+	s, ok = truncateString("abc", 0)
+	assert.False(t, ok)
+	assert.Equal(t, "abc", s)
+}
@@ -0,0 +1,106 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// InvalidEventMetadataKey is the metadata key under which a diagnostic event logged by a ValidatingLogger describes
+// the invalid event it replaces.
+const InvalidEventMetadataKey = "invalid_event"
+
+// InvalidEventAction is what a ValidatingLogger does with an invalid event.
+type InvalidEventAction int
+
+const (
+	// ReplaceInvalidEvents replaces each invalid event with an Error event describing the problem. This is the default.
+	ReplaceInvalidEvents InvalidEventAction = iota
+	// DropInvalidEvents drops invalid events.
+	DropInvalidEvents
+)
+
+// A ValidatingLogger checks events before forwarding them to another Logger, to catch bugs which would otherwise
+// produce useless log lines. An event is invalid if it has an empty message or ID, or a severity which isn't between
+// TraceSeverity and CriticalSeverity. Invalid events are counted and, by default, replaced with a diagnostic event.
+type ValidatingLogger struct {
+	next    Logger
+	action  InvalidEventAction
+	invalid uint64
+}
+
+// A ValidatingOption configures a ValidatingLogger.
+type ValidatingOption func(*ValidatingLogger)
+
+// WithInvalidEventAction sets what the logger does with invalid events.
+func WithInvalidEventAction(action InvalidEventAction) ValidatingOption {
+	return func(l *ValidatingLogger) {
+		l.action = action
+	}
+}
+
+// NewValidatingLogger creates a ValidatingLogger which forwards valid events to next.
+func NewValidatingLogger(next Logger, opts ...ValidatingOption) *ValidatingLogger {
+	l := &ValidatingLogger{
+		next: next,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log forwards the valid events, and drops or replaces the invalid ones.
+func (l *ValidatingLogger) Log(evs ...Event) {
+	forwarded := make([]Event, 0, len(evs))
+	for _, e := range evs {
+		err := validateEvent(e)
+		if err == nil {
+			forwarded = append(forwarded, e)
+			continue
+		}
+		atomic.AddUint64(&l.invalid, 1)
+		if l.action == ReplaceInvalidEvents {
+			forwarded = append(forwarded, Eventf(ErrorSeverity, e.Context, "Invalid log event: %v", err,
+				map[string]interface{}{
+					InvalidEventMetadataKey: map[string]interface{}{
+						"id":       e.Id,
+						"severity": int(e.Severity),
+						"message":  e.Message,
+					},
+				}))
+		}
+	}
+	if len(forwarded) > 0 {
+		l.next.Log(forwarded...)
+	}
+}
+
+// Flush the underlying logger.
+func (l *ValidatingLogger) Flush() error {
+	return l.next.Flush()
+}
+
+// Enabled reports whether the underlying logger would log events of the given severity.
+func (l *ValidatingLogger) Enabled(sev Severity) bool {
+	return enabled(l.next, sev)
+}
+
+// Invalid returns the number of invalid events the logger has received.
+func (l *ValidatingLogger) Invalid() uint64 {
+	return atomic.LoadUint64(&l.invalid)
+}
+
+// validateEvent returns an error describing why the event is invalid, or nil if it is valid.
+func validateEvent(e Event) error {
+	switch {
+	case e.Message == "":
+		return errors.New("empty message")
+	case e.Id == "":
+		return errors.New("empty ID")
+	case e.Severity < TraceSeverity || e.Severity > CriticalSeverity:
+		return fmt.Errorf("severity %d out of range", e.Severity)
+	default:
+		return nil
+	}
+}
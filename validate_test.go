@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func malformedEvents() []Event {
+	ctx := context.Background()
+	valid := Eventf(InfoSeverity, ctx, "Valid")
+	emptyMessage := Eventf(InfoSeverity, ctx, "")
+	noID := Eventf(WarnSeverity, ctx, "No ID")
+	noID.Id = ""
+	badSeverity := Eventf(InfoSeverity, ctx, "Bad severity")
+	badSeverity.Severity = CriticalSeverity + 1
+	zeroSeverity := Event{Id: "e1", Message: "Zero severity"}
+	return []Event{valid, emptyMessage, noID, badSeverity, zeroSeverity}
+}
+
+func TestValidatingLoggerReplaces(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewValidatingLogger(next)
+	logger.Log(malformedEvents()...)
+
+	events := next.Events()
+	require.Len(t, events, 5)
+	assert.Equal(t, []string{
+		"Valid",
+		"Invalid log event: empty message",
+		"Invalid log event: empty ID",
+		"Invalid log event: severity 7 out of range",
+		"Invalid log event: severity 0 out of range",
+	}, messages(events))
+	for _, e := range events[1:] {
+		assert.Equal(t, ErrorSeverity, e.Severity)
+		assert.NotNil(t, e.Error)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"id":       "",
+		"severity": int(WarnSeverity),
+		"message":  "No ID",
+	}, events[2].Metadata[InvalidEventMetadataKey])
+	assert.Equal(t, uint64(4), logger.Invalid())
+}
+
+func TestValidatingLoggerDrops(t *testing.T) {
+	next := NewInMemoryLogger()
+	logger := NewValidatingLogger(next, WithInvalidEventAction(DropInvalidEvents))
+	logger.Log(malformedEvents()...)
+
+	assert.Equal(t, []string{"Valid"}, messages(next.Events()))
+	assert.Equal(t, uint64(4), logger.Invalid())
+
+	logger.Log(Event{})
+	assert.Equal(t, 1, next.Len())
+	assert.Equal(t, uint64(5), logger.Invalid())
+}
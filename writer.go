@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"io"
+	"sync"
+)
+
+// writeErrors records the errors returned by a writer-backed logger's writes, so that they can be surfaced from Flush
+// rather than being swallowed by Log.
+type writeErrors struct {
+	m sync.Mutex
+	// last is the result of the most recent write.
+	last error
+	// unflushed is the first error since the last call to Flush.
+	unflushed error
+}
+
+func (w *writeErrors) record(err error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.last = err
+	if err != nil && w.unflushed == nil {
+		w.unflushed = err
+	}
+}
+
+// LastError returns the error from the most recent write, or nil if it succeeded. This can be used by health checks
+// to detect that the log sink is broken.
+func (w *writeErrors) LastError() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.last
+}
+
+// takeError returns the first error since it was last called, and resets it.
+func (w *writeErrors) takeError() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	err := w.unflushed
+	w.unflushed = nil
+	return err
+}
+
+// A WriterLogger writes each event on its own line to an io.Writer.
+type WriterLogger struct {
+	writeErrors
+	m sync.Mutex
+	w io.Writer
+}
+
+// NewWriterLogger creates a WriterLogger which writes to w.
+func NewWriterLogger(w io.Writer) *WriterLogger {
+	return &WriterLogger{
+		w: w,
+	}
+}
+
+// Log writes the events to the underlying writer. Any write error is reported by the next call to Flush.
+func (l *WriterLogger) Log(evs ...Event) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, e := range evs {
+		_, err := io.WriteString(l.w, e.String()+"\n")
+		l.record(err)
+	}
+}
+
+// Flush the underlying writer if it supports flushing, and return the first error encountered since the last Flush.
+func (l *WriterLogger) Flush() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if f, ok := l.w.(interface{ Flush() error }); ok {
+		l.record(f.Flush())
+	}
+	return l.takeError()
+}
@@ -0,0 +1,54 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+func TestWriterLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewWriterLogger(buf)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "one"), Eventf(InfoSeverity, context.Background(), "two"))
+	assert.NoError(t, logger.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "INFO one")
+	assert.Contains(t, lines[1], "INFO two")
+}
+
+func TestWriterLoggerWriteError(t *testing.T) {
+	diskFull := errors.New("disk full")
+	w := &failingWriter{err: diskFull}
+	logger := NewWriterLogger(w)
+
+	logger.Log(Eventf(InfoSeverity, context.Background(), "foo"))
+	assert.Equal(t, diskFull, logger.LastError())
+	assert.Equal(t, diskFull, logger.Flush())
+
+	// The error is only reported by Flush once
+	assert.NoError(t, logger.Flush())
+
+	// A successful write clears the last error
+	w.err = nil
+	logger.Log(Eventf(InfoSeverity, context.Background(), "foo"))
+	assert.NoError(t, logger.LastError())
+	assert.NoError(t, logger.Flush())
+}